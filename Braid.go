@@ -0,0 +1,154 @@
+package gomaze
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Braid initializes a grid into a braid maze, a maze with loops. The
+// grid is first initialized with another Initer, after which a
+// fraction of the dead ends in the resulting maze are removed by
+// linking them to a neighbour. Removing dead ends introduces loops, so
+// that there are multiple paths between cells in the maze.
+type Braid struct {
+	init Initer
+	rng  *rand.Rand
+
+	// p is the fraction of dead ends to remove
+	p float64
+}
+
+// NewBraid returns a new Braid which initializes a grid with init and
+// then removes a fraction p of the dead ends
+func NewBraid(seed int64, init Initer, p float64) (Initer, error) {
+	if init == nil {
+		return nil, fmt.Errorf("newBraid: initer must be non-nil")
+	}
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return nil, fmt.Errorf("newBraid: fraction %v ∉ [0, 1]", p)
+	}
+
+	return &Braid{
+		init: init,
+		rng:  rand.New(rand.NewSource(seed)),
+		p:    p,
+	}, nil
+}
+
+// Init initializes a grid into a braid maze
+func (b *Braid) Init(g *Grid) error {
+	if err := b.init.Init(g); err != nil {
+		return fmt.Errorf("init: could not initialize grid: %v", err)
+	}
+
+	if err := g.Braid(b.p, b.rng); err != nil {
+		return fmt.Errorf("init: %v", err)
+	}
+	return nil
+}
+
+// DeadEnds returns the cells of the grid which are linked to exactly
+// one other cell
+func (g *Grid) DeadEnds() []*Cell {
+	deadEnds := make([]*Cell, 0)
+	for _, cell := range g.cells {
+		if len(cell.links) == 1 {
+			deadEnds = append(deadEnds, cell)
+		}
+	}
+
+	return deadEnds
+}
+
+// Braid removes a fraction p of the dead ends in the grid by linking
+// each removed dead end to a neighbour it is not yet linked to. Of the
+// n dead ends in the grid, round(p * n) are removed, chosen uniformly at
+// random. Where possible, a dead end is linked to a neighbour which is
+// itself a dead end, so that both dead ends are removed with a single
+// link. If the last dead end to remove can only be linked to another
+// dead end, both are removed, so that one more dead end than requested
+// may be removed.
+func (g *Grid) Braid(p float64, rng *rand.Rand) error {
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return fmt.Errorf("braid: fraction %v ∉ [0, 1]", p)
+	}
+
+	deadEnds := g.DeadEnds()
+	rng.Shuffle(len(deadEnds), func(i, j int) {
+		deadEnds[i], deadEnds[j] = deadEnds[j], deadEnds[i]
+	})
+	remaining := int(math.Round(p * float64(len(deadEnds))))
+
+	// Dead ends which could only be removed along with another dead
+	// end when only one more dead end was to be removed
+	var paired []*Cell
+
+	for _, cell := range deadEnds {
+		if remaining <= 0 {
+			break
+		}
+
+		// The cell may no longer be a dead end if a previous dead end
+		// was linked to it
+		if len(cell.links) != 1 {
+			continue
+		}
+
+		unlinked, deadEndNeighbours := unlinkedNeighbours(cell)
+		switch {
+		case len(deadEndNeighbours) > 0 && remaining > 1:
+			cell.Link(deadEndNeighbours[rng.Intn(len(deadEndNeighbours))])
+			remaining -= 2
+
+		case len(unlinked) > len(deadEndNeighbours):
+			others := make([]*Cell, 0, len(unlinked))
+			for _, neighbour := range unlinked {
+				if len(neighbour.links) != 1 {
+					others = append(others, neighbour)
+				}
+			}
+			cell.Link(others[rng.Intn(len(others))])
+			remaining--
+
+		case len(deadEndNeighbours) > 0:
+			paired = append(paired, cell)
+		}
+	}
+
+	for _, cell := range paired {
+		if remaining <= 0 {
+			break
+		}
+		if len(cell.links) != 1 {
+			continue
+		}
+
+		_, deadEndNeighbours := unlinkedNeighbours(cell)
+		if len(deadEndNeighbours) > 0 {
+			cell.Link(deadEndNeighbours[rng.Intn(len(deadEndNeighbours))])
+			remaining -= 2
+		}
+	}
+
+	return nil
+}
+
+// unlinkedNeighbours returns the neighbours of cell which it is not
+// linked to, as well as those of these neighbours which are dead ends
+func unlinkedNeighbours(cell *Cell) ([]*Cell, []*Cell) {
+	unlinked := make([]*Cell, 0, 3)
+	deadEnds := make([]*Cell, 0, 3)
+	for _, neighbour := range cell.Neighbours() {
+		if neighbour == nil || cell.Linked(neighbour) {
+			continue
+		}
+
+		unlinked = append(unlinked, neighbour)
+		if len(neighbour.links) == 1 {
+			deadEnds = append(deadEnds, neighbour)
+		}
+	}
+
+	return unlinked, deadEnds
+}
@@ -0,0 +1,69 @@
+package gomaze
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBraidFraction(t *testing.T) {
+	for _, p := range []float64{0, 0.25, 0.5, 1} {
+		for seed := int64(0); seed < 10; seed++ {
+			g := NewGrid(12, 15)
+			if err := NewBacktracking(seed).Init(g); err != nil {
+				t.Fatal(err)
+			}
+
+			before := len(g.DeadEnds())
+			if err := g.Braid(p, rand.New(rand.NewSource(seed))); err != nil {
+				t.Fatal(err)
+			}
+			after := len(g.DeadEnds())
+
+			want := int(math.Round(p * float64(before)))
+			if before-after != want {
+				t.Errorf("p = %v, seed = %v: removed %v of %v dead ends, "+
+					"want %v", p, seed, before-after, before, want)
+			}
+		}
+	}
+}
+
+func TestBraidExtraDeadEnd(t *testing.T) {
+	// The grid is a U-shaped corridor whose two dead ends are each
+	// other's only unlinked neighbour, so removing one of them must
+	// remove both
+	g := NewGrid(2, 2)
+	cell := func(x, y int) *Cell {
+		c, err := g.CellAt(x, y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	cell(0, 0).Link(cell(1, 0))
+	cell(1, 0).Link(cell(1, 1))
+	cell(1, 1).Link(cell(0, 1))
+
+	if n := len(g.DeadEnds()); n != 2 {
+		t.Fatalf("expected 2 dead ends but got %v", n)
+	}
+	if err := g.Braid(0.5, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.DeadEnds()); n != 0 {
+		t.Errorf("expected both dead ends to be removed but %v remain", n)
+	}
+}
+
+func TestBraidInvalidFraction(t *testing.T) {
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := NewBraid(1, NewBacktracking(1), p); err == nil {
+			t.Errorf("NewBraid: expected error for fraction %v", p)
+		}
+		g := NewGrid(3, 3)
+		if err := g.Braid(p, rand.New(rand.NewSource(1))); err == nil {
+			t.Errorf("Braid: expected error for fraction %v", p)
+		}
+	}
+}
@@ -11,6 +11,7 @@ type Cell struct {
 	north, south, east, west *Cell // Neighbour cells
 
 	links map[*Cell]struct{} // Can travel to any cell in links
+	grid  *Grid              // Grid containing the cell, if any
 }
 
 // NewCell creates and returns a new cell at row r and column c
@@ -131,6 +132,10 @@ func (c *Cell) South() *Cell {
 func (c *Cell) Link(new *Cell) {
 	c.links[new] = struct{}{}
 	new.links[c] = struct{}{}
+
+	if c.grid != nil && c.grid.linkHook != nil {
+		c.grid.linkHook(c, new, true)
+	}
 }
 
 // Unlink unlinks the receiver to old suvh that a player can no longer
@@ -139,6 +144,10 @@ func (c *Cell) Link(new *Cell) {
 func (c *Cell) Unlink(old *Cell) {
 	delete(c.links, old)
 	delete(old.links, c)
+
+	if c.grid != nil && c.grid.linkHook != nil {
+		c.grid.linkHook(c, old, false)
+	}
 }
 
 // Linked returns whether the receiver is linked to cell
@@ -148,13 +157,25 @@ func (c *Cell) Linked(cell *Cell) bool {
 }
 
 // Links returns the links of the receiver. Equivalently, this function
-// returns the cells that can be moved to from the receiver.
+// returns the cells that can be moved to from the receiver. Linked
+// neighbours are returned in the same order as in Neighbours, so that
+// searches over the links of cells are deterministic.
 func (c *Cell) Links() []*Cell {
-	keys := make([]*Cell, len(c.links))
-	i := 0
-	for key := range c.links {
-		keys[i] = key
-		i++
+	keys := make([]*Cell, 0, len(c.links))
+	for _, neighbour := range c.Neighbours() {
+		if neighbour != nil && c.Linked(neighbour) {
+			keys = append(keys, neighbour)
+		}
+	}
+
+	// Cells may also be linked to cells which are not neighbours
+	if len(keys) < len(c.links) {
+		for key := range c.links {
+			if key != c.north && key != c.south && key != c.east &&
+				key != c.west {
+				keys = append(keys, key)
+			}
+		}
 	}
 
 	return keys
@@ -0,0 +1,101 @@
+package gomaze
+
+// Distances tracks the distance from a root cell to every cell
+// reachable from the root, where the distance between two cells is
+// the number of moves between linked cells needed to travel from one
+// to the other.
+type Distances struct {
+	root  *Cell
+	dists map[*Cell]int
+
+	// order holds the reachable cells in order of increasing distance
+	// from the root
+	order []*Cell
+}
+
+// NewDistances returns the distances from root to every cell reachable
+// from root. Since all moves between linked cells have the same cost,
+// Dijkstra's algorithm reduces to a breadth-first search.
+func NewDistances(root *Cell) *Distances {
+	d := &Distances{
+		root:  root,
+		dists: map[*Cell]int{root: 0},
+		order: []*Cell{root},
+	}
+
+	for i := 0; i < len(d.order); i++ {
+		cell := d.order[i]
+		for _, neighbour := range cell.Links() {
+			if _, seen := d.dists[neighbour]; !seen {
+				d.dists[neighbour] = d.dists[cell] + 1
+				d.order = append(d.order, neighbour)
+			}
+		}
+	}
+
+	return d
+}
+
+// Root returns the root cell from which distances are measured
+func (d *Distances) Root() *Cell {
+	return d.root
+}
+
+// Distance returns the distance from the root to cell and whether cell
+// is reachable from the root
+func (d *Distances) Distance(cell *Cell) (int, bool) {
+	dist, ok := d.dists[cell]
+	return dist, ok
+}
+
+// Cells returns all cells reachable from the root in order of
+// increasing distance from the root
+func (d *Distances) Cells() []*Cell {
+	return d.order
+}
+
+// Len returns the number of cells reachable from the root
+func (d *Distances) Len() int {
+	return len(d.order)
+}
+
+// Max returns the cell farthest from the root and its distance from
+// the root. If multiple cells are equally far from the root, the first
+// one discovered is returned.
+func (d *Distances) Max() (*Cell, int) {
+	i := len(d.order) - 1
+	maxDist := d.dists[d.order[i]]
+	for i > 0 && d.dists[d.order[i-1]] == maxDist {
+		i--
+	}
+
+	return d.order[i], maxDist
+}
+
+// Values returns the distance from the root to each cell of g, indexed
+// as in Grid.Index. Cells unreachable from the root have a distance
+// of -1.
+func (d *Distances) Values(g *Grid) []int {
+	dists := make([]int, g.Len())
+	for i, cell := range g.Cells() {
+		if dist, ok := d.dists[cell]; ok {
+			dists[i] = dist
+		} else {
+			dists[i] = -1
+		}
+	}
+
+	return dists
+}
+
+// LongestPath returns two cells in the grid which are far apart, found
+// with two breadth-first searches. The first search finds the cell
+// farthest from the top left cell, and the second finds the cell
+// farthest from that cell. If the grid is a perfect maze, the two
+// cells are the farthest apart of any two cells in the maze.
+func (g *Grid) LongestPath() (*Cell, *Cell) {
+	from, _ := NewDistances(g.cells[0]).Max()
+	to, _ := NewDistances(from).Max()
+
+	return from, to
+}
@@ -0,0 +1,216 @@
+package gomaze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// EllerRow is a single row of a maze generated with Eller's algorithm.
+// Rows are generated one at a time, and only the passages leading out
+// of the cells of the row to the east and to the south are stored.
+// Passages to the north and west are given by the previous row and
+// by the previous cell in the row respectively.
+type EllerRow struct {
+	Row int // Index of the row in the maze
+
+	// East[c] is whether the cell at column c is linked to the cell at
+	// column c+1. The last element is always false.
+	East []bool
+
+	// South[c] is whether the cell at column c is linked to the cell
+	// at column c in the next row. All elements are false in the last
+	// row.
+	South []bool
+}
+
+// Eller initializes a grid into a maze using Eller's algorithm
+type Eller struct {
+	rng *rand.Rand
+}
+
+// NewEller returns a new Eller
+func NewEller(seed int64) Initer {
+	return &Eller{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Init initializes a grid into a maze using Eller's algorithm
+func (e *Eller) Init(g *Grid) error {
+	err := ellerRows(e.rng, g.Rows(), g.Cols(), func(row EllerRow) error {
+		for c := 0; c < g.Cols(); c++ {
+			cell := g.cells[g.Index(c, row.Row)]
+			if row.East[c] {
+				cell.Link(cell.East())
+			}
+			if row.South[c] {
+				cell.Link(cell.South())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("init: %v", err)
+	}
+
+	return nil
+}
+
+// StreamEller generates a maze of dimensions rows ⨉ cols with Eller's
+// algorithm, calling emit with each row of the maze in order from top
+// to bottom. Only a single row of the maze is kept in memory at any
+// time, so that arbitrarily long mazes can be generated without
+// constructing a Grid. The slices of the EllerRow passed to emit are
+// reused between calls and must be copied if retained. If emit returns
+// an error, generation stops and the error is returned.
+func StreamEller(seed int64, rows, cols int, emit func(EllerRow) error) error {
+	rng := rand.New(rand.NewSource(seed))
+	if err := ellerRows(rng, rows, cols, emit); err != nil {
+		return fmt.Errorf("streamEller: %v", err)
+	}
+	return nil
+}
+
+// WriteEller generates a maze of dimensions rows ⨉ cols with Eller's
+// algorithm and writes its string representation to w one row at a
+// time. The output is identical to the output of Grid.String for the
+// same maze.
+func WriteEller(w io.Writer, seed int64, rows, cols int) error {
+	out := bufio.NewWriter(w)
+
+	out.WriteString("+")
+	for c := 0; c < cols; c++ {
+		out.WriteString("---+")
+	}
+	out.WriteString("\n")
+
+	err := StreamEller(seed, rows, cols, func(row EllerRow) error {
+		out.WriteString("|")
+		for c := 0; c < cols; c++ {
+			if row.East[c] {
+				out.WriteString("    ")
+			} else {
+				out.WriteString("   |")
+			}
+		}
+		out.WriteString("\n+")
+
+		for c := 0; c < cols; c++ {
+			if row.South[c] {
+				out.WriteString("   +")
+			} else {
+				out.WriteString("---+")
+			}
+		}
+		_, err := out.WriteString("\n")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("writeEller: %v", err)
+	}
+
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("writeEller: could not flush output: %v", err)
+	}
+	return nil
+}
+
+// ellerRows runs Eller's algorithm, generating rows ⨉ cols cells one
+// row at a time and calling emit with each row
+func ellerRows(rng *rand.Rand, rows, cols int, emit func(EllerRow) error) error {
+	if rows <= 0 || cols <= 0 {
+		return fmt.Errorf("ellerRows: invalid dimensions %v ⨉ %v", rows, cols)
+	}
+
+	// sets[c] is the set of the cell in column c of the current row.
+	// Cells in the same set are connected through previous rows.
+	sets := make([]int, cols)
+	for c := range sets {
+		sets[c] = c
+	}
+	nextSet := cols
+
+	row := EllerRow{
+		East:  make([]bool, cols),
+		South: make([]bool, cols),
+	}
+
+	// members tracks the columns in each set of the current row, which
+	// is needed to guarantee each set has at least one south passage
+	members := make(map[int][]int, cols)
+
+	for r := 0; r < rows; r++ {
+		row.Row = r
+		last := r == rows-1
+
+		// Randomly join adjacent cells in different sets. On the last
+		// row, all adjacent cells in different sets must be joined.
+		for c := 0; c < cols; c++ {
+			row.East[c] = false
+			row.South[c] = false
+
+			if c == cols-1 || sets[c] == sets[c+1] {
+				continue
+			}
+			if last || rng.Intn(2) == 0 {
+				row.East[c] = true
+				merge(sets, sets[c+1], sets[c])
+			}
+		}
+
+		if !last {
+			// Randomly add south passages, ensuring every set has at
+			// least one so that no set is disconnected from the maze
+			for c, set := range sets {
+				members[set] = append(members[set], c)
+			}
+
+			// Sets are visited in column order rather than map order
+			// so that generation is reproducible given a seed
+			for _, set := range sets {
+				columns, ok := members[set]
+				if !ok {
+					continue
+				}
+				delete(members, set)
+
+				carved := false
+				for _, c := range columns {
+					if rng.Intn(2) == 0 {
+						row.South[c] = true
+						carved = true
+					}
+				}
+				if !carved {
+					row.South[columns[rng.Intn(len(columns))]] = true
+				}
+			}
+		}
+
+		if err := emit(row); err != nil {
+			return err
+		}
+
+		// Cells without a north passage in the next row start in new
+		// sets
+		for c := range sets {
+			if !row.South[c] {
+				sets[c] = nextSet
+				nextSet++
+			}
+		}
+	}
+
+	return nil
+}
+
+// merge replaces all occurrences of set from in sets with set to
+func merge(sets []int, from, to int) {
+	for i := range sets {
+		if sets[i] == from {
+			sets[i] = to
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package gomaze
+
+// Maze implements the Environment interface
+var _ Environment = (*Maze)(nil)
+
+// Environment is a reinforcement learning environment
+type Environment interface {
+	// Reset resets the environment to some starting state and returns
+	// the starting state observation. If seed is non-nil, all random
+	// number generators of the environment are reseeded from *seed,
+	// otherwise they continue from their current state.
+	Reset(seed *int64) []float64
+
+	// Step takes a single environmental step given some action to take
+	Step(action int) (StepResult, error)
+
+	// ObservationSpec describes the observations of the environment
+	ObservationSpec() ObservationSpec
+
+	// ActionSpec describes the actions of the environment
+	ActionSpec() ActionSpec
+}
+
+// ObservationSpec describes the observations of an Environment.
+// Observations are flat vectors of Shape's product elements in
+// row-major order, where element i is bounded by Low[i] and High[i].
+type ObservationSpec struct {
+	Shape     []int
+	Low, High []float64
+}
+
+// ActionSpec describes the discrete actions of an Environment, which
+// are numbered from 0 to N-1
+type ActionSpec struct {
+	N int
+}
+
+// ObservationSpec describes the observations of the maze
+func (m *Maze) ObservationSpec() ObservationSpec {
+	switch m.obsType {
+	case EgocentricObs:
+		size := (2*m.window+1)*(2*m.window+1) + m.window*m.window
+		return unitSpec(size)
+
+	case WallSensorObs:
+		spec := unitSpec(m.wallSensorSize())
+		if m.sensorGoal {
+			// The goal direction is last, and is in [-1, 1]
+			spec.Low[len(spec.Low)-1] = -1.0
+			spec.Low[len(spec.Low)-2] = -1.0
+		}
+		return spec
+
+	case PixelObs:
+		spec := unitSpec(m.Len() * PixelChannels)
+		spec.Shape = []int{m.Rows(), m.Cols(), PixelChannels}
+		return spec
+
+	case RGBObs:
+		height, width := m.rgbShape()
+		spec := unitSpec(height * width * 3)
+		spec.Shape = []int{height, width, 3}
+		return spec
+	}
+
+	if m.oneHotState {
+		return unitSpec(m.Len())
+	}
+
+	return ObservationSpec{
+		Shape: []int{2},
+		Low:   []float64{0, 0},
+		High:  []float64{float64(m.Cols() - 1), float64(m.Rows() - 1)},
+	}
+}
+
+// unitSpec returns the ObservationSpec of observations with size
+// elements, each in [0, 1]
+func unitSpec(size int) ObservationSpec {
+	low := make([]float64, size)
+	high := make([]float64, size)
+	for i := range high {
+		high[i] = 1.0
+	}
+
+	return ObservationSpec{
+		Shape: []int{size},
+		Low:   low,
+		High:  high,
+	}
+}
+
+// ActionSpec describes the actions of the maze
+func (m *Maze) ActionSpec() ActionSpec {
+	return ActionSpec{N: Actions}
+}
@@ -0,0 +1,179 @@
+package gomaze
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+)
+
+// heatmapPaletteSize is the number of colours of the heatmap colour
+// map included in the palette of GIF frames
+const heatmapPaletteSize int = 128
+
+// GIFRecorder records images of a maze as the frames of an animated
+// GIF. Frames can be recorded during an episode by attaching the
+// recorder to a maze with Maze.SetRecorder, during the generation of a
+// maze with RecordGeneration, or manually with CaptureGrid and
+// CaptureMaze.
+type GIFRecorder struct {
+	opts   ImageOptions
+	delay  int // Delay between frames in 100ths of a second
+	frames []*image.Paletted
+	delays []int
+}
+
+// NewGIFRecorder returns a new GIFRecorder which renders frames with
+// opts and shows each frame for delay 100ths of a second
+func NewGIFRecorder(opts ImageOptions, delay int) (*GIFRecorder, error) {
+	if delay < 0 {
+		return nil, fmt.Errorf("newGIFRecorder: delay must be non-negative "+
+			"but got %v", delay)
+	}
+	if _, _, err := opts.sizes(); err != nil {
+		return nil, fmt.Errorf("newGIFRecorder: %v", err)
+	}
+
+	return &GIFRecorder{
+		opts:  opts,
+		delay: delay,
+	}, nil
+}
+
+// CaptureGrid records the current state of g as a frame
+func (r *GIFRecorder) CaptureGrid(g *Grid) error {
+	if err := r.capture(g, r.opts); err != nil {
+		return fmt.Errorf("captureGrid: %v", err)
+	}
+	return nil
+}
+
+// CaptureMaze records the current state of m, including the start,
+// goal, and player markers, as a frame
+func (r *GIFRecorder) CaptureMaze(m *Maze) error {
+	if err := r.capture(m.Grid, m.imageOptions(r.opts)); err != nil {
+		return fmt.Errorf("captureMaze: %v", err)
+	}
+	return nil
+}
+
+// RecordGeneration initializes g with init, recording a frame after
+// every given number of links or unlinks between cells, as well as a
+// frame of the initial and final grid. Setting every to 1 records a
+// frame for each passage carved by init.
+func (r *GIFRecorder) RecordGeneration(g *Grid, init Initer,
+	every int) error {
+	if every < 1 {
+		return fmt.Errorf("recordGeneration: frame interval must be "+
+			"positive but got %v", every)
+	}
+
+	if err := r.capture(g, r.opts); err != nil {
+		return fmt.Errorf("recordGeneration: %v", err)
+	}
+
+	var captureErr error
+	changes := 0
+	g.SetLinkHook(func(a, b *Cell, linked bool) {
+		changes++
+		if changes%every == 0 && captureErr == nil {
+			captureErr = r.capture(g, r.opts)
+		}
+	})
+	defer g.SetLinkHook(nil)
+
+	if err := init.Init(g); err != nil {
+		return fmt.Errorf("recordGeneration: could not initialize grid: %v",
+			err)
+	}
+	if captureErr != nil {
+		return fmt.Errorf("recordGeneration: %v", captureErr)
+	}
+
+	if changes%every != 0 {
+		if err := r.capture(g, r.opts); err != nil {
+			return fmt.Errorf("recordGeneration: %v", err)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of frames recorded
+func (r *GIFRecorder) Len() int {
+	return len(r.frames)
+}
+
+// Reset removes all recorded frames
+func (r *GIFRecorder) Reset() {
+	r.frames = nil
+	r.delays = nil
+}
+
+// Encode writes the recorded frames to w as an animated GIF
+func (r *GIFRecorder) Encode(w io.Writer) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("encode: no frames recorded")
+	}
+
+	err := gif.EncodeAll(w, &gif.GIF{
+		Image: r.frames,
+		Delay: r.delays,
+	})
+	if err != nil {
+		return fmt.Errorf("encode: could not encode GIF: %v", err)
+	}
+	return nil
+}
+
+// capture renders g with opts and records the image as a frame
+func (r *GIFRecorder) capture(g *Grid, opts ImageOptions) error {
+	s, err := g.scene(opts)
+	if err != nil {
+		return fmt.Errorf("capture: %v", err)
+	}
+
+	img := s.image()
+	frame := image.NewPaletted(img.Bounds(), gifPalette())
+	draw.Draw(frame, frame.Bounds(), img, image.Point{}, draw.Src)
+
+	r.frames = append(r.frames, frame)
+	r.delays = append(r.delays, r.delay)
+	return nil
+}
+
+// gifPalette returns the palette of GIF frames, which consists of all
+// colours used in images of mazes
+func gifPalette() color.Palette {
+	palette := color.Palette{
+		backgroundColour,
+		wallColour,
+		pathColour,
+		startColour,
+		goalColour,
+		playerColour,
+	}
+
+	for i := 0; i < heatmapPaletteSize; i++ {
+		t := float64(i) / float64(heatmapPaletteSize-1)
+		palette = append(palette, heatmapColour(t))
+	}
+	return palette
+}
+
+// SetRecorder attaches a GIFRecorder to the maze, which records a
+// frame each time the maze is reset and each time a step is taken. If
+// r is nil, any attached recorder is removed. An error is returned if
+// the recorder cannot render the maze, for example if its heatmap does
+// not have a value for each cell of the maze.
+func (m *Maze) SetRecorder(r *GIFRecorder) error {
+	if r != nil {
+		if _, err := m.scene(m.imageOptions(r.opts)); err != nil {
+			return fmt.Errorf("setRecorder: %v", err)
+		}
+	}
+
+	m.recorder = r
+	return nil
+}
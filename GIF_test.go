@@ -0,0 +1,53 @@
+package gomaze
+
+import "testing"
+
+func TestNewGIFRecorderInvalid(t *testing.T) {
+	for _, opts := range []ImageOptions{
+		{CellSize: 2, WallThickness: 5},
+		{CellSize: 4, WallThickness: 4},
+		{CellSize: -1},
+		{WallThickness: -1},
+	} {
+		if _, err := NewGIFRecorder(opts, 1); err == nil {
+			t.Errorf("expected error for options %+v", opts)
+		}
+	}
+	if _, err := NewGIFRecorder(ImageOptions{}, -1); err == nil {
+		t.Errorf("expected error for negative delay")
+	}
+}
+
+func TestSetRecorder(t *testing.T) {
+	m, err := NewMaze(3, 3, -1, -1, -1, -1, NewBacktracking(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The heatmap has the wrong number of values for the maze
+	r, err := NewGIFRecorder(ImageOptions{Heatmap: []float64{1}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetRecorder(r); err == nil {
+		t.Errorf("expected error for heatmap of the wrong size")
+	}
+
+	r, err = NewGIFRecorder(ImageOptions{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetRecorder(r); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Reset(nil)
+	for _, action := range []int{1, 3} {
+		if _, err := m.Step(action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Len() != 3 {
+		t.Errorf("expected 3 frames but got %v", r.Len())
+	}
+}
@@ -2,13 +2,16 @@ package gomaze
 
 import (
 	"fmt"
-	"strings"
 )
 
 // Grid implements a grid of cells
 type Grid struct {
 	rows, cols int
 	cells      []*Cell
+
+	// linkHook is called whenever two cells of the grid are linked or
+	// unlinked
+	linkHook func(a, b *Cell, linked bool)
 }
 
 // NewGrid returns a new grid of cells. Each cell has all four walls
@@ -24,7 +27,9 @@ func NewGrid(rows, cols int) *Grid {
 	// Create the grid
 	for r := 0; r < rows; r++ {
 		for c := 0; c < cols; c++ {
-			cells[g.Index(c, r)] = NewCell(r, c)
+			cell := NewCell(r, c)
+			cell.grid = g
+			cells[g.Index(c, r)] = cell
 		}
 	}
 
@@ -54,13 +59,22 @@ func NewGrid(rows, cols int) *Grid {
 	return g
 }
 
+// SetLinkHook sets a function which is called whenever two cells of
+// the grid are linked or unlinked, with linked true if the cells were
+// linked and false if they were unlinked. This allows the generation
+// of a maze by an Initer to be observed. If hook is nil, the hook is
+// removed.
+func (g *Grid) SetLinkHook(hook func(a, b *Cell, linked bool)) {
+	g.linkHook = hook
+}
+
 // CellAt returns the cell at column x and row y in the grid
 func (g *Grid) CellAt(x, y int) (*Cell, error) {
-	if x > g.Cols() {
+	if x < 0 || x >= g.Cols() {
 		return nil, fmt.Errorf("cellAt: column index out of range [%v] with "+
 			"length %v", x, g.Cols())
 	}
-	if y > g.Rows() {
+	if y < 0 || y >= g.Rows() {
 		return nil, fmt.Errorf("cellAt: row index out of range [%v] with "+
 			"length %v", y, g.Rows())
 	}
@@ -98,41 +112,5 @@ func (g *Grid) Cells() []*Cell {
 
 // String returns a string representation of the grid
 func (g *Grid) String() string {
-	var out strings.Builder
-	out.WriteString("+")
-
-	for c := 0; c < g.Cols(); c++ {
-		out.WriteString("---+")
-	}
-	out.WriteString("\n")
-
-	for r := 0; r < g.Rows(); r++ {
-		top := "|"
-		bottom := "+"
-		for c := 0; c < g.Cols(); c++ {
-			cell := g.cells[g.Index(c, r)]
-			body := "   "
-			var eastBoundary string
-			if cell.Linked(cell.East()) {
-				eastBoundary = " "
-			} else {
-				eastBoundary = "|"
-			}
-			top = top + body + eastBoundary
-
-			var southBoundary string
-			if cell.Linked(cell.South()) {
-				southBoundary = "   "
-			} else {
-				southBoundary = "---"
-			}
-			corner := "+"
-			bottom = bottom + southBoundary + corner
-		}
-		out.WriteString(top)
-		out.WriteString("\n")
-		out.WriteString(bottom)
-		out.WriteString("\n")
-	}
-	return out.String()
+	return defaultTextRenderer().RenderGrid(g, nil)
 }
@@ -0,0 +1,131 @@
+package gomaze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Selector selects the next cell to grow a GrowingTree from. Given the
+// number of active cells n, a Selector returns an index in [0, n) into
+// the active cells, which are ordered from oldest to newest.
+type Selector func(n int, rng *rand.Rand) int
+
+// NewestSelector selects the most recently added active cell, which
+// makes GrowingTree equivalent to Iterative
+func NewestSelector(n int, rng *rand.Rand) int {
+	return n - 1
+}
+
+// OldestSelector selects the least recently added active cell
+func OldestSelector(n int, rng *rand.Rand) int {
+	return 0
+}
+
+// RandomSelector selects an active cell uniformly at random, which
+// makes GrowingTree similar to SimplifiedPrim
+func RandomSelector(n int, rng *rand.Rand) int {
+	return rng.Intn(n)
+}
+
+// NewMixedSelector returns a Selector which selects cells with a with
+// probability p and with b otherwise. For example, NewMixedSelector(
+// 0.75, NewestSelector, RandomSelector) selects the newest cell 75% of
+// the time and a random cell 25% of the time.
+func NewMixedSelector(p float64, a, b Selector) (Selector, error) {
+	if p < 0 || p > 1 {
+		return nil, fmt.Errorf("newMixedSelector: probability %v ∉ [0, 1]",
+			p)
+	}
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("newMixedSelector: selectors must be non-nil")
+	}
+
+	return func(n int, rng *rand.Rand) int {
+		if rng.Float64() < p {
+			return a(n, rng)
+		}
+		return b(n, rng)
+	}, nil
+}
+
+// GrowingTree initializes a grid into a maze using the growing tree
+// algorithm. The texture of the maze is determined by the Selector
+// used to choose which active cell to grow the maze from next.
+type GrowingTree struct {
+	rng      *rand.Rand
+	selector Selector
+}
+
+// NewGrowingTree returns a new GrowingTree which selects the next cell
+// to grow the maze from using selector
+func NewGrowingTree(seed int64, selector Selector) (Initer, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("newGrowingTree: selector must be non-nil")
+	}
+
+	return &GrowingTree{
+		rng:      rand.New(rand.NewSource(seed)),
+		selector: selector,
+	}, nil
+}
+
+// Init initializes a grid into a maze using the growing tree algorithm
+func (t *GrowingTree) Init(g *Grid) error {
+	visited := make(map[*Cell]struct{}, g.Len())
+
+	// Choose a random starting cell
+	r := t.rng.Intn(g.Rows())
+	c := t.rng.Intn(g.Cols())
+	currentCell, err := g.CellAt(c, r)
+	if err != nil {
+		return fmt.Errorf("init: could not get first cell: %v", err)
+	}
+
+	// Active cells, ordered from oldest to newest
+	active := make([]*Cell, 0, 100)
+	active = append(active, currentCell)
+	visited[currentCell] = struct{}{}
+
+	for len(active) > 0 {
+		index := t.selector(len(active), t.rng)
+		if index < 0 || index >= len(active) {
+			return fmt.Errorf("init: selector returned index %v ∉ [0, %v)",
+				index, len(active))
+		}
+		currentCell = active[index]
+
+		// Choose random unvisited neighbour
+		neighbours := make([]*Cell, 0, 4)
+		for _, cell := range currentCell.Neighbours() {
+			if cell == nil {
+				continue
+			}
+			// Check if the cell has been visited
+			if _, ok := visited[cell]; !ok {
+				neighbours = append(neighbours, cell)
+			}
+		}
+
+		if len(neighbours) == 0 {
+			// The cell has no unvisited neighbours, so it can no
+			// longer grow the maze
+			switch index {
+			case 0:
+				active = active[1:]
+
+			case len(active) - 1:
+				active = active[:len(active)-1]
+
+			default:
+				active = append(active[:index], active[index+1:]...)
+			}
+		} else {
+			neighbourCell := neighbours[t.rng.Intn(len(neighbours))]
+			currentCell.Link(neighbourCell)
+			visited[neighbourCell] = struct{}{}
+			active = append(active, neighbourCell)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package gomaze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// HuntAndKill initializes a grid into a maze using the hunt-and-kill
+// algorithm
+type HuntAndKill struct {
+	rng *rand.Rand
+}
+
+// NewHuntAndKill returns a new HuntAndKill
+func NewHuntAndKill(seed int64) Initer {
+	return &HuntAndKill{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Init initializes a grid into a maze using the hunt-and-kill
+// algorithm. A random walk is performed over unvisited cells, linking
+// each cell to the previous. When the walk reaches a cell with no
+// unvisited neighbours, the grid is scanned row by row for an
+// unvisited cell next to a visited cell, which is linked to its
+// visited neighbour and used to start the next walk.
+func (h *HuntAndKill) Init(g *Grid) error {
+	visited := make(map[*Cell]struct{}, g.Len())
+
+	// Choose a random starting cell
+	r := h.rng.Intn(g.Rows())
+	c := h.rng.Intn(g.Cols())
+	currentCell, err := g.CellAt(c, r)
+	if err != nil {
+		return fmt.Errorf("init: could not get first cell: %v", err)
+	}
+	visited[currentCell] = struct{}{}
+
+	// All rows before huntRow contain only visited cells, so they do
+	// not need to be scanned again when hunting
+	huntRow := 0
+
+	for currentCell != nil {
+		// Kill: walk to a random unvisited neighbour
+		unvisited := h.neighbours(currentCell, visited, false)
+		if len(unvisited) > 0 {
+			neighbourCell := unvisited[h.rng.Intn(len(unvisited))]
+			currentCell.Link(neighbourCell)
+			visited[neighbourCell] = struct{}{}
+			currentCell = neighbourCell
+			continue
+		}
+
+		// Hunt: find an unvisited cell next to a visited cell
+		currentCell = nil
+		for r := huntRow; r < g.Rows() && currentCell == nil; r++ {
+			rowVisited := true
+			for c := 0; c < g.Cols(); c++ {
+				cell := g.cells[g.Index(c, r)]
+				if _, ok := visited[cell]; ok {
+					continue
+				}
+				rowVisited = false
+
+				visitedNeighbours := h.neighbours(cell, visited, true)
+				if len(visitedNeighbours) > 0 {
+					index := h.rng.Intn(len(visitedNeighbours))
+					cell.Link(visitedNeighbours[index])
+					visited[cell] = struct{}{}
+					currentCell = cell
+					break
+				}
+			}
+
+			if rowVisited && r == huntRow {
+				huntRow++
+			}
+		}
+	}
+
+	return nil
+}
+
+// neighbours returns the neighbours of cell which have been visited
+// if wantVisited is true, or which have not been visited otherwise
+func (h *HuntAndKill) neighbours(cell *Cell, visited map[*Cell]struct{},
+	wantVisited bool) []*Cell {
+	neighbours := make([]*Cell, 0, 4)
+	for _, neighbour := range cell.Neighbours() {
+		if neighbour == nil {
+			continue
+		}
+		if _, ok := visited[neighbour]; ok == wantVisited {
+			neighbours = append(neighbours, neighbour)
+		}
+	}
+	return neighbours
+}
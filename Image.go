@@ -0,0 +1,351 @@
+package gomaze
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+const (
+	defaultCellSize      int = 20 // Default width of cells in images
+	defaultWallThickness int = 2  // Default thickness of walls in images
+)
+
+// Colours of images
+var (
+	backgroundColour = color.RGBA{255, 255, 255, 255}
+	wallColour       = color.RGBA{0, 0, 0, 255}
+	pathColour       = color.RGBA{255, 140, 0, 255}
+	startColour      = color.RGBA{80, 110, 255, 255}
+	goalColour       = color.RGBA{0, 180, 0, 255}
+	playerColour     = color.RGBA{220, 0, 0, 255}
+
+	// heatmapColours are the stops of the colour map used for
+	// heatmaps, from the lowest to highest values
+	heatmapColours = []color.RGBA{
+		{68, 1, 84, 255},
+		{33, 145, 140, 255},
+		{253, 231, 37, 255},
+	}
+)
+
+// ImageOptions determines how mazes are rendered as images
+type ImageOptions struct {
+	// CellSize is the width and height of each cell in pixels. If 0,
+	// a default of 20 is used.
+	CellSize int
+
+	// WallThickness is the thickness of walls in pixels. If 0, a
+	// default of 2 is used.
+	WallThickness int
+
+	// Start, Goal, and Player are the cells to mark as the starting
+	// cell, goal cell, and cell of the player. Cells which are nil are
+	// not marked. When rendering a Maze, these default to the maze's
+	// start, goal, and player cells unless HideMarkers is set.
+	Start, Goal, Player *Cell
+	HideMarkers         bool
+
+	// Path is a sequence of neighbouring cells, such as the solution
+	// returned by ShortestPath, which is drawn over the maze
+	Path []*Cell
+
+	// Heatmap is a scalar value for each cell, indexed as in
+	// Grid.Index, such as a value function or visitation counts. If
+	// non-nil, each cell is coloured according to its value. Cells
+	// with a value of NaN or ±Inf are not coloured.
+	Heatmap []float64
+}
+
+// rect is a filled rectangle in an image
+type rect struct {
+	x, y, w, h int
+	colour     color.RGBA
+}
+
+// circle is a filled circle in an image
+type circle struct {
+	x, y, r int
+	colour  color.RGBA
+}
+
+// scene holds the shapes which make up an image of a maze, in the
+// order they are drawn
+type scene struct {
+	width, height int
+	rects         []rect
+	path          [][2]int // Centres of cells along the path
+	pathWidth     int
+	circles       []circle
+}
+
+// WritePNG writes a PNG image of the grid to w
+func (g *Grid) WritePNG(w io.Writer, opts ImageOptions) error {
+	s, err := g.scene(opts)
+	if err != nil {
+		return fmt.Errorf("writePNG: %v", err)
+	}
+
+	if err := png.Encode(w, s.image()); err != nil {
+		return fmt.Errorf("writePNG: could not encode image: %v", err)
+	}
+	return nil
+}
+
+// WriteSVG writes an SVG image of the grid to w
+func (g *Grid) WriteSVG(w io.Writer, opts ImageOptions) error {
+	s, err := g.scene(opts)
+	if err != nil {
+		return fmt.Errorf("writeSVG: %v", err)
+	}
+
+	if err := s.writeSVG(w); err != nil {
+		return fmt.Errorf("writeSVG: could not write image: %v", err)
+	}
+	return nil
+}
+
+// WritePNG writes a PNG image of the maze to w
+func (m *Maze) WritePNG(w io.Writer, opts ImageOptions) error {
+	return m.Grid.WritePNG(w, m.imageOptions(opts))
+}
+
+// WriteSVG writes an SVG image of the maze to w
+func (m *Maze) WriteSVG(w io.Writer, opts ImageOptions) error {
+	return m.Grid.WriteSVG(w, m.imageOptions(opts))
+}
+
+// imageOptions returns opts with unset markers set to the maze's
+// start, goal, and player cells
+func (m *Maze) imageOptions(opts ImageOptions) ImageOptions {
+	if opts.HideMarkers {
+		return opts
+	}
+
+	if opts.Start == nil {
+		opts.Start = m.start
+	}
+	if opts.Goal == nil {
+		opts.Goal = m.goal
+	}
+	if opts.Player == nil {
+		opts.Player = m.player.in
+	}
+	return opts
+}
+
+// sizes returns the cell size and wall thickness of images, with
+// defaults used for unset values
+func (o ImageOptions) sizes() (int, int, error) {
+	cs, wt := o.CellSize, o.WallThickness
+	if cs == 0 {
+		cs = defaultCellSize
+	}
+	if wt == 0 {
+		wt = defaultWallThickness
+	}
+	if cs < 0 || wt < 0 || wt >= cs {
+		return 0, 0, fmt.Errorf("invalid cell size %v and wall thickness %v",
+			cs, wt)
+	}
+	return cs, wt, nil
+}
+
+// scene returns the shapes which make up an image of the grid
+func (g *Grid) scene(opts ImageOptions) (*scene, error) {
+	cs, wt, err := opts.sizes()
+	if err != nil {
+		return nil, fmt.Errorf("scene: %v", err)
+	}
+	if opts.Heatmap != nil && len(opts.Heatmap) != g.Len() {
+		return nil, fmt.Errorf("scene: heatmap has %v values but grid has "+
+			"%v cells", len(opts.Heatmap), g.Len())
+	}
+
+	s := &scene{
+		width:     g.Cols()*cs + wt,
+		height:    g.Rows()*cs + wt,
+		pathWidth: int(math.Max(float64(cs/5), 1)),
+	}
+
+	// Heatmap
+	if opts.Heatmap != nil {
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, v := range opts.Heatmap {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+
+		for i, cell := range g.cells {
+			v := opts.Heatmap[i]
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+
+			t := 0.5
+			if high > low {
+				t = (v - low) / (high - low)
+			}
+			s.rects = append(s.rects, rect{cell.Col() * cs, cell.Row() * cs,
+				cs + wt, cs + wt, heatmapColour(t)})
+		}
+	}
+
+	// Walls, starting with the northern and western boundaries
+	s.rects = append(s.rects, rect{0, 0, s.width, wt, wallColour})
+	s.rects = append(s.rects, rect{0, 0, wt, s.height, wallColour})
+	for _, cell := range g.cells {
+		x, y := cell.Col()*cs, cell.Row()*cs
+		if !cell.CanMoveEast() {
+			s.rects = append(s.rects, rect{x + cs, y, wt, cs + wt, wallColour})
+		}
+		if !cell.CanMoveSouth() {
+			s.rects = append(s.rects, rect{x, y + cs, cs + wt, wt, wallColour})
+		}
+	}
+
+	centre := func(c *Cell) (int, int) {
+		return c.Col()*cs + (cs+wt)/2, c.Row()*cs + (cs+wt)/2
+	}
+
+	// Solution path
+	for _, cell := range opts.Path {
+		x, y := centre(cell)
+		s.path = append(s.path, [2]int{x, y})
+	}
+
+	// Markers
+	for _, marker := range []struct {
+		cell   *Cell
+		colour color.RGBA
+		scale  float64
+	}{
+		{opts.Start, startColour, 0.35},
+		{opts.Goal, goalColour, 0.35},
+		{opts.Player, playerColour, 0.25},
+	} {
+		if marker.cell != nil {
+			x, y := centre(marker.cell)
+			r := int(math.Max(marker.scale*float64(cs-wt), 1))
+			s.circles = append(s.circles, circle{x, y, r, marker.colour})
+		}
+	}
+
+	return s, nil
+}
+
+// heatmapColour returns the colour of a value t ∈ [0, 1] in the
+// heatmap colour map. Values outside [0, 1] are clamped, and NaN is
+// treated as 0.
+func heatmapColour(t float64) color.RGBA {
+	if math.IsNaN(t) {
+		t = 0
+	}
+	t = math.Max(0, math.Min(1, t)) * float64(len(heatmapColours)-1)
+	i := int(math.Min(t, float64(len(heatmapColours)-2)))
+	t -= float64(i)
+
+	from, to := heatmapColours[i], heatmapColours[i+1]
+	lerp := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + t*(float64(b)-float64(a))))
+	}
+
+	return color.RGBA{lerp(from.R, to.R), lerp(from.G, to.G),
+		lerp(from.B, to.B), 255}
+}
+
+// image rasterizes the scene
+func (s *scene) image() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, s.width, s.height))
+	fill := func(r rect) {
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				img.SetRGBA(x, y, r.colour)
+			}
+		}
+	}
+
+	fill(rect{0, 0, s.width, s.height, backgroundColour})
+	for _, r := range s.rects {
+		fill(r)
+	}
+
+	// Path segments are axis-aligned, so each can be drawn as a
+	// rectangle
+	half := s.pathWidth / 2
+	for i := 1; i < len(s.path); i++ {
+		from, to := s.path[i-1], s.path[i]
+		x0, x1 := minMax(from[0], to[0])
+		y0, y1 := minMax(from[1], to[1])
+		fill(rect{x0 - half, y0 - half, x1 - x0 + s.pathWidth,
+			y1 - y0 + s.pathWidth, pathColour})
+	}
+
+	for _, c := range s.circles {
+		for y := c.y - c.r; y <= c.y+c.r; y++ {
+			for x := c.x - c.r; x <= c.x+c.r; x++ {
+				dx, dy := x-c.x, y-c.y
+				if dx*dx+dy*dy <= c.r*c.r {
+					img.SetRGBA(x, y, c.colour)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// writeSVG writes the scene to w as an SVG image
+func (s *scene) writeSVG(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" "+
+		"width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", s.width,
+		s.height, s.width, s.height)
+	fmt.Fprintf(out, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+		s.width, s.height, svgColour(backgroundColour))
+
+	for _, r := range s.rects {
+		fmt.Fprintf(out, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" "+
+			"fill=\"%s\"/>\n", r.x, r.y, r.w, r.h, svgColour(r.colour))
+	}
+
+	if len(s.path) > 0 {
+		out.WriteString("<polyline points=\"")
+		for i, p := range s.path {
+			if i > 0 {
+				out.WriteString(" ")
+			}
+			fmt.Fprintf(out, "%d,%d", p[0], p[1])
+		}
+		fmt.Fprintf(out, "\" fill=\"none\" stroke=\"%s\" stroke-width=\"%d\" "+
+			"stroke-linecap=\"square\" stroke-linejoin=\"miter\"/>\n",
+			svgColour(pathColour), s.pathWidth)
+	}
+
+	for _, c := range s.circles {
+		fmt.Fprintf(out, "<circle cx=\"%d\" cy=\"%d\" r=\"%d\" fill=\"%s\"/>\n",
+			c.x, c.y, c.r, svgColour(c.colour))
+	}
+
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+// svgColour returns the SVG representation of colour c
+func svgColour(c color.RGBA) string {
+	return fmt.Sprintf("rgb(%d,%d,%d)", c.R, c.G, c.B)
+}
+
+// minMax returns the smaller and larger of a and b
+func minMax(a, b int) (int, int) {
+	if a < b {
+		return a, b
+	}
+	return b, a
+}
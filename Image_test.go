@@ -0,0 +1,60 @@
+package gomaze
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestHeatmapNonFinite(t *testing.T) {
+	g := NewGrid(3, 3)
+	if err := NewBacktracking(1).Init(g); err != nil {
+		t.Fatal(err)
+	}
+
+	heatmap := []float64{math.Inf(-1), math.Inf(1), math.NaN(), 0, 1, 2, 3,
+		4, 5}
+	err := g.WritePNG(io.Discard, ImageOptions{Heatmap: heatmap})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only finite values are coloured
+	s, err := g.scene(ImageOptions{Heatmap: heatmap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coloured := 0
+	for _, r := range s.rects {
+		if r.colour != wallColour {
+			coloured++
+		}
+	}
+	if coloured != 6 {
+		t.Errorf("expected 6 coloured cells but got %v", coloured)
+	}
+
+	// All non-finite values must not panic
+	heatmap = []float64{math.Inf(-1), math.Inf(1), math.NaN(), math.Inf(1),
+		math.Inf(-1), math.NaN(), math.Inf(1), math.Inf(1), math.NaN()}
+	var buf bytes.Buffer
+	if err := g.WriteSVG(&buf, ImageOptions{Heatmap: heatmap}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeatmapColourClamped(t *testing.T) {
+	for _, v := range []float64{math.NaN(), math.Inf(-1), -1} {
+		if got := heatmapColour(v); got != heatmapColours[0] {
+			t.Errorf("heatmapColour(%v) = %v, want %v", v, got,
+				heatmapColours[0])
+		}
+	}
+	last := heatmapColours[len(heatmapColours)-1]
+	for _, v := range []float64{math.Inf(1), 2} {
+		if got := heatmapColour(v); got != last {
+			t.Errorf("heatmapColour(%v) = %v, want %v", v, got, last)
+		}
+	}
+}
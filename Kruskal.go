@@ -0,0 +1,115 @@
+package gomaze
+
+import (
+	"math/rand"
+)
+
+// wall is a wall between two neighbouring cells in a grid
+type wall struct {
+	from, to *Cell
+}
+
+// disjointSet implements a union-find structure over the cells of a
+// grid, indexed by each cell's single-dimensional grid index.
+type disjointSet struct {
+	parent []int
+	rank   []int
+}
+
+// newDisjointSet returns a new disjointSet with n singleton sets
+func newDisjointSet(n int) *disjointSet {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	return &disjointSet{
+		parent: parent,
+		rank:   make([]int, n),
+	}
+}
+
+// find returns the representative of the set containing i
+func (d *disjointSet) find(i int) int {
+	for d.parent[i] != i {
+		// Path halving
+		d.parent[i] = d.parent[d.parent[i]]
+		i = d.parent[i]
+	}
+	return i
+}
+
+// union merges the sets containing i and j and returns whether the
+// two were in different sets before merging.
+func (d *disjointSet) union(i, j int) bool {
+	rootI, rootJ := d.find(i), d.find(j)
+	if rootI == rootJ {
+		return false
+	}
+
+	switch {
+	case d.rank[rootI] < d.rank[rootJ]:
+		d.parent[rootI] = rootJ
+
+	case d.rank[rootI] > d.rank[rootJ]:
+		d.parent[rootJ] = rootI
+
+	default:
+		d.parent[rootJ] = rootI
+		d.rank[rootI]++
+	}
+	return true
+}
+
+// Kruskal initializes a grid into a maze using randomized Kruskal's
+// algorithm
+type Kruskal struct {
+	rng *rand.Rand
+}
+
+// NewKruskal returns a new Kruskal
+func NewKruskal(seed int64) Initer {
+	return &Kruskal{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Init initializes a grid into a maze using randomized Kruskal's
+// algorithm. Every interior wall of the grid is considered in a random
+// order, and a wall is removed if the cells on either side of it are
+// not yet connected.
+func (k *Kruskal) Init(g *Grid) error {
+	// Collect all interior walls. Only the south and east walls of each
+	// cell are considered so that each wall is added exactly once.
+	walls := make([]wall, 0, 2*g.Len())
+	for _, cell := range g.Cells() {
+		if cell.South() != nil {
+			walls = append(walls, wall{cell, cell.South()})
+		}
+		if cell.East() != nil {
+			walls = append(walls, wall{cell, cell.East()})
+		}
+	}
+
+	k.rng.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	sets := newDisjointSet(g.Len())
+	linked := 0
+	for _, w := range walls {
+		if linked == g.Len()-1 {
+			// All cells are connected
+			break
+		}
+
+		from := g.Index(w.from.Col(), w.from.Row())
+		to := g.Index(w.to.Col(), w.to.Row())
+		if sets.union(from, to) {
+			w.from.Link(w.to)
+			linked++
+		}
+	}
+
+	return nil
+}
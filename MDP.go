@@ -0,0 +1,86 @@
+package gomaze
+
+// MDP is a tabular Markov decision process. States are indexed as in
+// Grid.Index and actions are indexed as in Maze.Step.
+type MDP struct {
+	// P is the transition tensor, where P[s][a][s'] is the probability
+	// of transitioning to state s' after taking action a in state s
+	P [][][]float64
+
+	// R is the reward tensor, where R[s][a] is the expected reward
+	// received after taking action a in state s
+	R [][]float64
+
+	// Terminal is the terminal mask, where Terminal[s] is whether
+	// state s is terminal. Terminal states are absorbing, transitioning
+	// back to themselves with a reward of 0 under all actions.
+	Terminal []bool
+}
+
+// transition is a possible outcome of taking an action in a cell
+type transition struct {
+	action int // The action actually taken
+	next   *Cell
+	prob   float64
+}
+
+// transitions returns the possible outcomes of taking action in cell
+// c of the maze
+func (m *Maze) transitions(c *Cell, action int) []transition {
+	if m.slip == nil {
+		return []transition{{action: action, next: move(c, action), prob: 1.0}}
+	}
+
+	actions, probs := m.slip.actions(action)
+	transitions := make([]transition, len(actions))
+	for i := range actions {
+		transitions[i] = transition{
+			action: actions[i],
+			next:   move(c, actions[i]),
+			prob:   probs[i],
+		}
+	}
+	return transitions
+}
+
+// reward returns the reward for transitioning from cell prev to cell
+// next by taking action
+func (m *Maze) reward(prev *Cell, action int, next *Cell) float64 {
+	return m.rewarder.Reward(m, prev, action, next, prev == next)
+}
+
+// MDP returns the maze as a tabular Markov decision process with
+// the same dynamics and rewards as Step. The transition tensor has
+// Len() ⨉ Actions ⨉ Len() elements, so this is only practical for
+// small mazes.
+func (m *Maze) MDP() *MDP {
+	n := m.Len()
+	mdp := &MDP{
+		P:        make([][][]float64, n),
+		R:        make([][]float64, n),
+		Terminal: make([]bool, n),
+	}
+
+	for s, cell := range m.cells {
+		mdp.P[s] = make([][]float64, Actions)
+		mdp.R[s] = make([]float64, Actions)
+		mdp.Terminal[s] = cell == m.goal
+
+		for a := 0; a < Actions; a++ {
+			mdp.P[s][a] = make([]float64, n)
+
+			if mdp.Terminal[s] {
+				mdp.P[s][a][s] = 1.0
+				continue
+			}
+
+			for _, t := range m.transitions(cell, a) {
+				next := m.Index(t.next.Col(), t.next.Row())
+				mdp.P[s][a][next] += t.prob
+				mdp.R[s][a] += t.prob * m.reward(cell, t.action, t.next)
+			}
+		}
+	}
+
+	return mdp
+}
@@ -5,6 +5,7 @@ package gomaze
 import (
 	"bufio"
 	"fmt"
+	"math/rand"
 	"os"
 	"strings"
 )
@@ -52,6 +53,29 @@ func (p *player) MoveEast() {
 	}
 }
 
+// move returns the cell that a player in cell c moves to when taking
+// action. Actions 0, 1, 2, and 3 move the player north, south, west,
+// and east respectively. If there is a wall in the direction of
+// movement, the player stays in c.
+func move(c *Cell, action int) *Cell {
+	switch {
+	case action == 0 && c.CanMoveNorth():
+		return c.North()
+
+	case action == 1 && c.CanMoveSouth():
+		return c.South()
+
+	case action == 2 && c.CanMoveWest():
+		return c.West()
+
+	case action == 3 && c.CanMoveEast():
+		return c.East()
+
+	default:
+		return c
+	}
+}
+
 // Maze implements a maze.
 type Maze struct {
 	*Grid // The grid of cells
@@ -63,6 +87,126 @@ type Maze struct {
 	// should be (x, y)-like or one-hot encodings of the (x, y)
 	// coordinates of the player in the maze.
 	oneHotState bool
+
+	// obsType determines the type of state observations, and window
+	// is the size of the window for egocentric observations.
+	// sensorPosition and sensorGoal determine whether wall sensor
+	// observations include the player's position and the direction
+	// of the goal respectively. scale is the number of pixels per
+	// block in RGB observations.
+	obsType        ObsType
+	window         int
+	sensorPosition bool
+	sensorGoal     bool
+	scale          int
+
+	// placement randomly samples the start and goal cells. If nil, the
+	// start and goal cells are fixed.
+	placement *placement
+
+	// slip determines how the player slips when taking actions. If
+	// nil, transitions are deterministic.
+	slip *slip
+
+	// rewarder determines the rewards given to the player
+	rewarder Rewarder
+
+	// maxSteps is the maximum number of steps in an episode, or 0 if
+	// episodes are not truncated. steps is the number of steps taken
+	// in the current episode.
+	maxSteps int
+	steps    int
+
+	// stepInfo determines whether Step fills the Info of its results
+	stepInfo bool
+
+	// recorder records frames of the maze when stepping and resetting
+	recorder *GIFRecorder
+}
+
+// Streams of random numbers used by a maze, from which distinct seeds
+// are derived when the maze is reseeded
+const (
+	placementStream uint64 = iota + 1
+	slipStream
+)
+
+// deriveSeed returns the seed of the given stream of random numbers
+// derived from seed. Seeds are derived with the SplitMix64 finalizer so
+// that the seeds of different streams are unrelated.
+func deriveSeed(seed int64, stream uint64) int64 {
+	z := uint64(seed) + stream*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// placement samples start and goal cells of a maze at random such
+// that the length of the shortest path between the two is in
+// [minDist, maxDist]
+type placement struct {
+	rng              *rand.Rand
+	seed             int64 // Seed rng was last seeded with
+	minDist, maxDist int
+
+	// resample determines whether a new start and goal are sampled
+	// each time the maze is reset
+	resample bool
+
+	// invalid caches whether each cell, indexed as in Grid.Index, is
+	// known to have no cells at a valid distance from it, so that each
+	// invalid start is searched from at most once. The grid of a maze
+	// does not change after the maze is created, so the cache is never
+	// invalidated.
+	invalid    []bool
+	numInvalid int
+}
+
+// sample samples a start and goal cell from g. A start cell is chosen
+// uniformly at random from all cells which have at least one cell at a
+// valid distance from it, and the goal is chosen uniformly at random
+// from all cells at a valid distance from the start.
+func (p *placement) sample(g *Grid) (*Cell, *Cell, error) {
+	if p.invalid == nil {
+		p.invalid = make([]bool, g.Len())
+	}
+
+	// Sample start cells uniformly until one has a valid goal, skipping
+	// cells already known to have none
+	for p.numInvalid < g.Len() {
+		i := p.rng.Intn(g.Len())
+		if p.invalid[i] {
+			continue
+		}
+
+		start := g.cells[i]
+		if goals := p.goals(start); len(goals) > 0 {
+			return start, goals[p.rng.Intn(len(goals))], nil
+		}
+		p.invalid[i] = true
+		p.numInvalid++
+	}
+
+	return nil, nil, fmt.Errorf("sample: no cells with distance in "+
+		"[%v, %v]", p.minDist, p.maxDist)
+}
+
+// goals returns the cells at a valid distance from start
+func (p *placement) goals(start *Cell) []*Cell {
+	dists := NewDistances(start)
+
+	goals := make([]*Cell, 0)
+	for _, cell := range dists.Cells() {
+		dist, _ := dists.Distance(cell)
+		if dist > p.maxDist {
+			// Cells are ordered by increasing distance
+			break
+		}
+		if dist >= p.minDist {
+			goals = append(goals, cell)
+		}
+	}
+	return goals
 }
 
 // NewMaze returns a new maze of dimensions rows ⨉ cols. The goal
@@ -75,7 +219,9 @@ type Maze struct {
 func NewMaze(rows, cols int, goalRow, goalCol int, startRow, startCol int,
 	init Initer, oneHotState bool) (*Maze, error) {
 	g := NewGrid(rows, cols)
-	init.Init(g)
+	if err := init.Init(g); err != nil {
+		return nil, fmt.Errorf("newMaze: could not initialize maze: %v", err)
+	}
 
 	// Get the goal cell
 	var goal *Cell
@@ -83,7 +229,7 @@ func NewMaze(rows, cols int, goalRow, goalCol int, startRow, startCol int,
 	if goalRow < 0 || goalCol < 0 {
 		goal, err = g.CellAt(cols-1, rows-1)
 	} else {
-		goal, err = g.CellAt(goalRow, goalCol)
+		goal, err = g.CellAt(goalCol, goalRow)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("newMaze: could not get goal position: %v",
@@ -95,20 +241,90 @@ func NewMaze(rows, cols int, goalRow, goalCol int, startRow, startCol int,
 	if startRow < 0 || startCol < 0 {
 		playerStart, err = g.CellAt(0, 0)
 	} else {
-		playerStart, err = g.CellAt(startRow, startCol)
+		playerStart, err = g.CellAt(startCol, startRow)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("newMaze: could not get start position: %v",
 			err)
 	}
 
+	return newMaze(g, playerStart, goal, oneHotState), nil
+}
+
+// NewMazeLongestPath returns a new maze of dimensions rows ⨉ cols,
+// where the starting and goal cells are the two cells farthest apart
+// in the maze. These cells are found with two breadth-first searches:
+// the first from an arbitrary cell to find the cell farthest from it,
+// which is used as the start, and the second from the start to find
+// the cell farthest from the start, which is used as the goal. For
+// perfect mazes, this gives the longest shortest path in the maze.
+// For mazes with loops, such as those generated by Braid, the path
+// found is long but may not be the longest. The oneHotState parameter
+// determines if state observations returned by Step() and Reset()
+// should be one-hot or (x, y) positions.
+func NewMazeLongestPath(rows, cols int, init Initer,
+	oneHotState bool) (*Maze, error) {
+	g := NewGrid(rows, cols)
+	if err := init.Init(g); err != nil {
+		return nil, fmt.Errorf("newMazeLongestPath: could not initialize "+
+			"maze: %v", err)
+	}
+
+	start, goal := g.LongestPath()
+
+	return newMaze(g, start, goal, oneHotState), nil
+}
+
+// NewMazeWithDistance returns a new maze of dimensions rows ⨉ cols,
+// where the starting and goal cells are sampled at random such that
+// the length of the shortest path between them is in
+// [minDist, maxDist], where minDist must be at least 1 so that the
+// start is never the goal. If resample is true, then a new start and
+// goal are sampled each time the maze is reset, otherwise they are
+// sampled only once when the maze is created. The oneHotState parameter
+// determines if state observations returned by Step() and Reset()
+// should be one-hot or (x, y) positions.
+func NewMazeWithDistance(rows, cols int, init Initer, minDist, maxDist int,
+	resample bool, seed int64, oneHotState bool) (*Maze, error) {
+	if minDist < 1 || maxDist < minDist {
+		return nil, fmt.Errorf("newMazeWithDistance: invalid distance "+
+			"range [%v, %v]", minDist, maxDist)
+	}
+
+	g := NewGrid(rows, cols)
+	if err := init.Init(g); err != nil {
+		return nil, fmt.Errorf("newMazeWithDistance: could not initialize "+
+			"maze: %v", err)
+	}
+
+	p := &placement{
+		rng:      rand.New(rand.NewSource(seed)),
+		seed:     seed,
+		minDist:  minDist,
+		maxDist:  maxDist,
+		resample: resample,
+	}
+	start, goal, err := p.sample(g)
+	if err != nil {
+		return nil, fmt.Errorf("newMazeWithDistance: could not place start "+
+			"and goal: %v", err)
+	}
+
+	m := newMaze(g, start, goal, oneHotState)
+	m.placement = p
+	return m, nil
+}
+
+// newMaze returns a new maze on grid g with the player at start
+func newMaze(g *Grid, start, goal *Cell, oneHotState bool) *Maze {
 	return &Maze{
 		Grid:        g,
-		player:      newPlayer(playerStart),
+		player:      newPlayer(start),
 		goal:        goal,
-		start:       playerStart,
+		start:       start,
 		oneHotState: oneHotState,
-	}, nil
+		rewarder:    NewStepCostReward(1.0),
+	}
 }
 
 // SetCell sets the current cell of the player
@@ -138,97 +354,143 @@ func (m *Maze) AtGoal() bool {
 	return m.player.in == m.goal
 }
 
+// StepResult is the result of taking a single environmental step
+type StepResult struct {
+	Obs    []float64 // The state observation after the step
+	Reward float64   // The reward for the step
+
+	// Terminated is whether the step led to an absorbing state. If
+	// true, the value of the next state is 0.
+	Terminated bool
+
+	// Truncated is whether the episode was ended by reaching the
+	// maximum episode length rather than an absorbing state. If true,
+	// the next state is not absorbing and its value should still be
+	// bootstrapped from.
+	Truncated bool
+
+	// Info holds auxiliary information about the step: the action
+	// taken after any slipping, whether the player moved into a wall,
+	// and the number of steps taken in the episode. Info is nil unless
+	// enabled with Maze.SetStepInfo, since it is allocated on each
+	// step.
+	Info map[string]interface{}
+}
+
 // Step takes a single environmental step given some action to take
 // in the maze. This function returns the state observation, the
-// reward, and whether or not the action led to an absorbing state.
-func (m *Maze) Step(action int) ([]float64, float64, bool, error) {
-	if action < 0 || action > Actions {
-		return nil, 0, false, fmt.Errorf("step: invalid action %v ∉ [%v, %v)",
+// reward given by the maze's Rewarder, whether or not the action led
+// to an absorbing state, and whether or not the episode was truncated
+// by reaching the maximum episode length.
+func (m *Maze) Step(action int) (StepResult, error) {
+	if action < 0 || action >= Actions {
+		return StepResult{}, fmt.Errorf("step: invalid action %v ∉ [%v, %v)",
 			action, 0, Actions)
 	}
 
-	switch action {
-	case 0:
-		m.MoveNorth()
-
-	case 1:
-		m.MoveSouth()
+	if m.slip != nil {
+		action = m.slip.action(action)
+	}
+	prev := m.player.in
+	m.player.in = move(prev, action)
+	m.steps++
+
+	if m.recorder != nil {
+		// SetRecorder checked that the recorder can render the maze, so
+		// this cannot fail
+		if err := m.recorder.CaptureMaze(m); err != nil {
+			panic(fmt.Sprintf("step: could not record frame: %v", err))
+		}
+	}
 
-	case 2:
-		m.MoveWest()
+	terminated := m.AtGoal()
+	result := StepResult{
+		Obs:        m.Obs(),
+		Reward:     m.reward(prev, action, m.player.in),
+		Terminated: terminated,
+		Truncated:  !terminated && m.maxSteps > 0 && m.steps >= m.maxSteps,
+	}
 
-	case 3:
-		m.MoveEast()
+	if m.stepInfo {
+		result.Info = map[string]interface{}{
+			"action":  action, // The action taken after any slipping
+			"hitWall": prev == m.player.in,
+			"steps":   m.steps,
+		}
 	}
+	return result, nil
+}
 
-	reward := -1.0
-	done := m.AtGoal()
-	if done {
-		reward = 0.0
+// SetStepInfo sets whether Step fills the Info of its results. Info is
+// disabled by default, since it allocates a map on each step.
+func (m *Maze) SetStepInfo(stepInfo bool) {
+	m.stepInfo = stepInfo
+}
+
+// SetMaxSteps sets the maximum number of steps in an episode, after
+// which Step reports that the episode was truncated. If maxSteps is
+// 0, episodes are never truncated.
+func (m *Maze) SetMaxSteps(maxSteps int) error {
+	if maxSteps < 0 {
+		return fmt.Errorf("setMaxSteps: maximum steps must be non-negative "+
+			"but got %v", maxSteps)
 	}
 
-	return m.Obs(), reward, done, nil
+	m.maxSteps = maxSteps
+	return nil
 }
 
-// Reset resets the environment to some starting state
-func (m *Maze) Reset() []float64 {
+// Steps returns the number of steps taken in the current episode
+func (m *Maze) Steps() int {
+	return m.steps
+}
+
+// Reset resets the environment to some starting state. If seed is
+// non-nil, the random number generators used for slipping and for
+// sampling the start and goal are reseeded with distinct seeds derived
+// from *seed, so that the two are not correlated. If the maze was
+// created with NewMazeWithDistance and resampling enabled, a new start
+// and goal are sampled.
+func (m *Maze) Reset(seed *int64) []float64 {
+	if seed != nil {
+		if m.placement != nil {
+			m.placement.seed = deriveSeed(*seed, placementStream)
+			m.placement.rng.Seed(m.placement.seed)
+		}
+		if m.slip != nil {
+			m.slip.seed = deriveSeed(*seed, slipStream)
+			m.slip.rng.Seed(m.slip.seed)
+		}
+	}
+
+	if m.placement != nil && m.placement.resample {
+		// A valid start and goal were found when the maze was created,
+		// and sample considers all start cells, so this cannot fail
+		start, goal, err := m.placement.sample(m.Grid)
+		if err != nil {
+			panic(fmt.Sprintf("reset: could not place start and goal: %v",
+				err))
+		}
+		m.start, m.goal = start, goal
+	}
+
 	m.player = newPlayer(m.start)
+	m.steps = 0
+
+	if m.recorder != nil {
+		// SetRecorder checked that the recorder can render the maze, so
+		// this cannot fail
+		if err := m.recorder.CaptureMaze(m); err != nil {
+			panic(fmt.Sprintf("reset: could not record frame: %v", err))
+		}
+	}
 
 	return m.Obs()
 }
 
 // String returns the string representation of the maze
 func (m *Maze) String() string {
-	var out strings.Builder
-	out.WriteString("+")
-
-	for c := 0; c < m.Cols(); c++ {
-		out.WriteString("---+")
-	}
-	out.WriteString("\n")
-
-	for r := 0; r < m.Rows(); r++ {
-		top := "|"
-		bottom := "+"
-		for c := 0; c < m.Cols(); c++ {
-			cell := m.cells[m.Index(c, r)]
-
-			var body string
-			if cell == m.player.in {
-				body = " x "
-			} else {
-				body = "   "
-			}
-
-			if cell == m.goal {
-				body = " 🏳 "
-			} else if cell != m.player.in {
-				body = "   "
-			}
-
-			var eastBoundary string
-			if cell.Linked(cell.East()) {
-				eastBoundary = " "
-			} else {
-				eastBoundary = "|"
-			}
-			top = top + body + eastBoundary
-
-			var southBoundary string
-			if cell.Linked(cell.South()) {
-				southBoundary = "   "
-			} else {
-				southBoundary = "---"
-			}
-			corner := "+"
-			bottom = bottom + southBoundary + corner
-		}
-		out.WriteString(top)
-		out.WriteString("\n")
-		out.WriteString(bottom)
-		out.WriteString("\n")
-	}
-	return out.String()
+	return defaultTextRenderer().RenderMaze(m, nil)
 }
 
 // OneHot returns a one-hot vector representing the position of the
@@ -286,6 +548,20 @@ func (m *Maze) Play() {
 
 // Obs returns the current state observation
 func (m *Maze) Obs() []float64 {
+	switch m.obsType {
+	case EgocentricObs:
+		return m.egocentric()
+
+	case WallSensorObs:
+		return m.wallSensor()
+
+	case PixelObs:
+		return m.pixels()
+
+	case RGBObs:
+		return m.rgb()
+	}
+
 	if m.oneHotState {
 		return m.OneHot()
 	}
@@ -0,0 +1,126 @@
+package gomaze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewMazeWithDistanceInvalidRange(t *testing.T) {
+	for _, r := range [][2]int{{0, 5}, {-1, 5}, {5, 4}} {
+		_, err := NewMazeWithDistance(5, 5, NewBacktracking(1), r[0], r[1],
+			false, 1, true)
+		if err == nil {
+			t.Errorf("expected error for distance range %v", r)
+		}
+	}
+
+	// No two cells of a 2 ⨉ 2 maze are further than 3 apart
+	_, err := NewMazeWithDistance(2, 2, NewBacktracking(1), 4, 10, false, 1,
+		true)
+	if err == nil {
+		t.Errorf("expected error for unreachable distance range")
+	}
+}
+
+func TestNewMazeWithDistanceResample(t *testing.T) {
+	const minDist, maxDist = 1, 3
+
+	braid, err := NewBraid(2, NewBacktracking(2), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMazeWithDistance(8, 8, braid, minDist, maxDist, true, 3,
+		true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for episode := 0; episode < 100; episode++ {
+		m.Reset(nil)
+		if m.AtGoal() {
+			t.Fatalf("episode %v started at the goal", episode)
+		}
+
+		dist, ok := NewDistances(m.start).Distance(m.goal)
+		if !ok || dist < minDist || dist > maxDist {
+			t.Fatalf("episode %v: distance %v between start and goal "+
+				"∉ [%v, %v]", episode, dist, minDist, maxDist)
+		}
+	}
+}
+
+func TestResetSeed(t *testing.T) {
+	m, err := NewMazeWithDistance(6, 6, NewBacktracking(4), 2, 6, true, 4,
+		true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetSlip(0.5, SlipRandom, 4); err != nil {
+		t.Fatal(err)
+	}
+	m.SetStepInfo(true)
+
+	// episode returns the start, goal, and actions actually taken in an
+	// episode of the maze
+	episode := func(seed *int64) []int {
+		m.Reset(seed)
+		trace := []int{m.Index(m.start.Col(), m.start.Row()),
+			m.Index(m.goal.Col(), m.goal.Row())}
+		for i := 0; i < 20; i++ {
+			result, err := m.Step(i % Actions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			trace = append(trace, result.Info["action"].(int))
+		}
+		return trace
+	}
+
+	seed := int64(-1)
+	want := episode(&seed)
+	if got := episode(&seed); !reflect.DeepEqual(got, want) {
+		t.Errorf("episodes differ after reseeding with the same seed: "+
+			"%v, want %v", got, want)
+	}
+
+	// The slipping and placement streams must be seeded differently
+	m.Reset(&seed)
+	if m.slip.seed == m.placement.seed {
+		t.Fatalf("slipping and placement reseeded with the same seed %v",
+			m.slip.seed)
+	}
+	if m.slip.rng.Int63() == m.placement.rng.Int63() {
+		t.Errorf("slipping and placement generators are correlated")
+	}
+
+	// Resetting without a seed must not reseed
+	slipSeed, placementSeed := m.slip.seed, m.placement.seed
+	m.Reset(nil)
+	if m.slip.seed != slipSeed || m.placement.seed != placementSeed {
+		t.Errorf("generators reseeded when resetting without a seed")
+	}
+}
+
+func TestStepInfo(t *testing.T) {
+	m, err := NewMaze(3, 3, -1, -1, -1, -1, NewBacktracking(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := m.Step(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Info != nil {
+		t.Errorf("info filled when disabled: %v", result.Info)
+	}
+
+	m.SetStepInfo(true)
+	result, err = m.Step(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Info["steps"] != 2 || result.Info["hitWall"] != true {
+		t.Errorf("unexpected info %v", result.Info)
+	}
+}
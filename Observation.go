@@ -0,0 +1,170 @@
+package gomaze
+
+import (
+	"fmt"
+	"math"
+)
+
+// ObsType determines the type of state observations returned by a
+// maze
+type ObsType int
+
+const (
+	// FullObs observations are the (x, y) coordinates of the player,
+	// or a one-hot encoding of the player's position if the maze was
+	// created with oneHotState set
+	FullObs ObsType = iota
+
+	// EgocentricObs observations are a local view of the maze centred
+	// on the player
+	EgocentricObs
+
+	// WallSensorObs observations encode which walls of the player's
+	// cell are open, optionally along with the player's position and
+	// the direction of the goal
+	WallSensorObs
+
+	// PixelObs observations are images of the maze with one pixel per
+	// cell and a channel per wall and object in the maze
+	PixelObs
+
+	// RGBObs observations are upscaled RGB images of the maze
+	RGBObs
+)
+
+// SetFullObs sets the maze to return fully observable state
+// observations. If oneHotState is true, then observations are one-hot
+// encodings of the player's position, otherwise observations are the
+// (x, y) coordinates of the player.
+func (m *Maze) SetFullObs(oneHotState bool) {
+	m.obsType = FullObs
+	m.oneHotState = oneHotState
+}
+
+// SetEgocentricObs sets the maze to return partial state observations
+// consisting of a k ⨉ k window of cells centred on the player, where k
+// must be odd.
+//
+// The window is encoded as a (2k+1) ⨉ (2k+1) grid of blocks in
+// row-major order, where each cell, each wall between cells, and each
+// corner between walls is a single block. Walls and corners are
+// encoded as 1, and cells and open passages are encoded as 0. Cells
+// outside the maze are encoded as walls. The blocks are followed by a
+// k ⨉ k one-hot grid, in row-major order, which indicates the position
+// of the goal if it is in the window.
+func (m *Maze) SetEgocentricObs(k int) error {
+	if k < 1 || k%2 == 0 {
+		return fmt.Errorf("setEgocentricObs: window size must be positive "+
+			"and odd but got %v", k)
+	}
+
+	m.obsType = EgocentricObs
+	m.window = k
+	return nil
+}
+
+// egocentric returns the egocentric observation of the maze
+func (m *Maze) egocentric() []float64 {
+	k := m.window
+	half := k / 2
+	size := 2*k + 1
+
+	obs := make([]float64, size*size+k*k)
+	walls := obs[:size*size]
+	goal := obs[size*size:]
+	for i := range walls {
+		walls[i] = 1.0
+	}
+
+	col, row := m.player.in.Col(), m.player.in.Row()
+	for dy := -half; dy <= half; dy++ {
+		for dx := -half; dx <= half; dx++ {
+			cell, err := m.CellAt(col+dx, row+dy)
+			if err != nil {
+				// Cell is outside the maze
+				continue
+			}
+
+			// Position of the cell's block in the window
+			x, y := 2*(dx+half)+1, 2*(dy+half)+1
+			walls[y*size+x] = 0.0
+
+			if cell.CanMoveNorth() {
+				walls[(y-1)*size+x] = 0.0
+			}
+			if cell.CanMoveSouth() {
+				walls[(y+1)*size+x] = 0.0
+			}
+			if cell.CanMoveWest() {
+				walls[y*size+x-1] = 0.0
+			}
+			if cell.CanMoveEast() {
+				walls[y*size+x+1] = 0.0
+			}
+
+			if cell == m.goal {
+				goal[(dy+half)*k+dx+half] = 1.0
+			}
+		}
+	}
+
+	return obs
+}
+
+// SetWallSensorObs sets the maze to return state observations which
+// encode whether the north, south, west, and east walls of the
+// player's cell are open, in that order, as 1 if open and 0 otherwise.
+// If position is true, these are followed by the (x, y) coordinates of
+// the player normalized to [0, 1]. If goalDirection is true, these are
+// followed by the (x, y) offset from the player to the goal normalized
+// to [-1, 1].
+func (m *Maze) SetWallSensorObs(position, goalDirection bool) {
+	m.obsType = WallSensorObs
+	m.sensorPosition = position
+	m.sensorGoal = goalDirection
+}
+
+// wallSensor returns the wall sensor observation of the maze
+func (m *Maze) wallSensor() []float64 {
+	obs := make([]float64, 4, m.wallSensorSize())
+
+	cell := m.player.in
+	for i, open := range []bool{
+		cell.CanMoveNorth(),
+		cell.CanMoveSouth(),
+		cell.CanMoveWest(),
+		cell.CanMoveEast(),
+	} {
+		if open {
+			obs[i] = 1.0
+		}
+	}
+
+	// Avoid dividing by zero in mazes with a single row or column
+	width := math.Max(float64(m.Cols()-1), 1)
+	height := math.Max(float64(m.Rows()-1), 1)
+
+	if m.sensorPosition {
+		obs = append(obs, float64(cell.Col())/width,
+			float64(cell.Row())/height)
+	}
+	if m.sensorGoal {
+		obs = append(obs, float64(m.goal.Col()-cell.Col())/width,
+			float64(m.goal.Row()-cell.Row())/height)
+	}
+
+	return obs
+}
+
+// wallSensorSize returns the number of elements in a wall sensor
+// observation
+func (m *Maze) wallSensorSize() int {
+	size := 4
+	if m.sensorPosition {
+		size += 2
+	}
+	if m.sensorGoal {
+		size += 2
+	}
+	return size
+}
@@ -0,0 +1,158 @@
+package gomaze
+
+import (
+	"fmt"
+)
+
+// Channels of pixel observations
+const (
+	NorthWallChannel = iota // 1 if the cell has a wall to the north
+	SouthWallChannel        // 1 if the cell has a wall to the south
+	WestWallChannel         // 1 if the cell has a wall to the west
+	EastWallChannel         // 1 if the cell has a wall to the east
+	PlayerChannel           // 1 if the player is in the cell
+	GoalChannel             // 1 if the cell is the goal
+	StartChannel            // 1 if the cell is the start
+
+	PixelChannels // Number of channels in pixel observations
+)
+
+// Colours of RGB observations
+var (
+	wallRGB   = [3]float64{0, 0, 0}
+	openRGB   = [3]float64{1, 1, 1}
+	playerRGB = [3]float64{1, 0, 0}
+	goalRGB   = [3]float64{0, 0.8, 0}
+	startRGB  = [3]float64{0.6, 0.6, 1}
+)
+
+// SetPixelObs sets the maze to return image-like state observations
+// of shape rows ⨉ cols ⨉ PixelChannels, flattened in row-major order
+// with the channel dimension last. The value at channel c of the
+// pixel at row r and column col is at index
+// (r * cols + col) * PixelChannels + c. The channels are described by
+// the constants NorthWallChannel through StartChannel. The shape of
+// the observations is given by ObservationSpec.
+func (m *Maze) SetPixelObs() {
+	m.obsType = PixelObs
+}
+
+// SetRGBObs sets the maze to return RGB image state observations. The
+// maze is drawn on a (2 * rows + 1) ⨉ (2 * cols + 1) grid of blocks,
+// where each cell, each wall between cells, and each corner between
+// walls is a single block, and each block is upscaled to scale ⨉ scale
+// pixels. Observations are flattened in row-major order with the
+// channel dimension last, and each channel is in [0, 1]. The shape of
+// the observations is given by ObservationSpec.
+func (m *Maze) SetRGBObs(scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("setRGBObs: scale must be positive but got %v",
+			scale)
+	}
+
+	m.obsType = RGBObs
+	m.scale = scale
+	return nil
+}
+
+// pixels returns the pixel observation of the maze
+func (m *Maze) pixels() []float64 {
+	obs := make([]float64, m.Len()*PixelChannels)
+
+	for i, cell := range m.cells {
+		pixel := obs[i*PixelChannels : (i+1)*PixelChannels]
+
+		if !cell.Linked(cell.North()) {
+			pixel[NorthWallChannel] = 1.0
+		}
+		if !cell.Linked(cell.South()) {
+			pixel[SouthWallChannel] = 1.0
+		}
+		if !cell.Linked(cell.West()) {
+			pixel[WestWallChannel] = 1.0
+		}
+		if !cell.Linked(cell.East()) {
+			pixel[EastWallChannel] = 1.0
+		}
+		if cell == m.player.in {
+			pixel[PlayerChannel] = 1.0
+		}
+		if cell == m.goal {
+			pixel[GoalChannel] = 1.0
+		}
+		if cell == m.start {
+			pixel[StartChannel] = 1.0
+		}
+	}
+
+	return obs
+}
+
+// rgb returns the RGB observation of the maze
+func (m *Maze) rgb() []float64 {
+	height, width := m.rgbShape()
+	obs := make([]float64, height*width*3)
+
+	blockCols := 2*m.Cols() + 1
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			colour := m.blockRGB(x/m.scale, y/m.scale, blockCols)
+			copy(obs[(y*width+x)*3:], colour[:])
+		}
+	}
+
+	return obs
+}
+
+// blockRGB returns the colour of the block at column x and row y of
+// the maze's grid of blocks
+func (m *Maze) blockRGB(x, y, blockCols int) [3]float64 {
+	// Corners are always walls
+	if x%2 == 0 && y%2 == 0 {
+		return wallRGB
+	}
+
+	// Walls are described by the cell to their west or north, and
+	// walls on the boundary of the maze are always closed
+	col, row := (x-1)/2, (y-1)/2
+	if x%2 == 0 {
+		if x == 0 || x == blockCols-1 {
+			return wallRGB
+		}
+		cell := m.cells[m.Index(col, row)]
+		if cell.CanMoveEast() {
+			return openRGB
+		}
+		return wallRGB
+	}
+	if y%2 == 0 {
+		if y == 0 || y == 2*m.Rows() {
+			return wallRGB
+		}
+		cell := m.cells[m.Index(col, row)]
+		if cell.CanMoveSouth() {
+			return openRGB
+		}
+		return wallRGB
+	}
+
+	cell := m.cells[m.Index(col, row)]
+	switch cell {
+	case m.player.in:
+		return playerRGB
+
+	case m.goal:
+		return goalRGB
+
+	case m.start:
+		return startRGB
+
+	default:
+		return openRGB
+	}
+}
+
+// rgbShape returns the height and width of RGB observations in pixels
+func (m *Maze) rgbShape() (int, int) {
+	return (2*m.Rows() + 1) * m.scale, (2*m.Cols() + 1) * m.scale
+}
@@ -0,0 +1,228 @@
+package gomaze
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+)
+
+// cellSet is a set of cells which supports constant-time insertion,
+// removal, membership tests, and uniform random sampling. Cells are
+// tracked by their single-dimensional grid index.
+type cellSet struct {
+	cells []*Cell
+	at    []int // at[i] is the position in cells of cell i, or -1
+	g     *Grid
+}
+
+// newCellSet returns a new, empty cellSet over the cells of g
+func newCellSet(g *Grid) *cellSet {
+	at := make([]int, g.Len())
+	for i := range at {
+		at[i] = -1
+	}
+
+	return &cellSet{
+		cells: make([]*Cell, 0, g.Cols()),
+		at:    at,
+		g:     g,
+	}
+}
+
+// has returns whether c is in the set
+func (s *cellSet) has(c *Cell) bool {
+	return s.at[s.g.Index(c.Col(), c.Row())] >= 0
+}
+
+// add adds c to the set if it is not already in the set
+func (s *cellSet) add(c *Cell) {
+	if s.has(c) {
+		return
+	}
+	s.at[s.g.Index(c.Col(), c.Row())] = len(s.cells)
+	s.cells = append(s.cells, c)
+}
+
+// remove removes c from the set if it is in the set
+func (s *cellSet) remove(c *Cell) {
+	index := s.g.Index(c.Col(), c.Row())
+	pos := s.at[index]
+	if pos < 0 {
+		return
+	}
+
+	// Move the last cell into the position of the removed cell
+	last := s.cells[len(s.cells)-1]
+	s.cells[pos] = last
+	s.at[s.g.Index(last.Col(), last.Row())] = pos
+	s.cells = s.cells[:len(s.cells)-1]
+	s.at[index] = -1
+}
+
+// random returns a uniformly random cell from the set
+func (s *cellSet) random(rng *rand.Rand) *Cell {
+	return s.cells[rng.Intn(len(s.cells))]
+}
+
+// len returns the number of cells in the set
+func (s *cellSet) len() int {
+	return len(s.cells)
+}
+
+// SimplifiedPrim initializes a grid into a maze using the simplified
+// version of randomized Prim's algorithm, where the next frontier cell
+// to add to the maze is chosen uniformly at random.
+type SimplifiedPrim struct {
+	rng *rand.Rand
+}
+
+// NewSimplifiedPrim returns a new SimplifiedPrim
+func NewSimplifiedPrim(seed int64) Initer {
+	return &SimplifiedPrim{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Init initializes a grid into a maze using the simplified Prim's
+// algorithm
+func (s *SimplifiedPrim) Init(g *Grid) error {
+	// Choose a random starting cell
+	r := s.rng.Intn(g.Rows())
+	c := s.rng.Intn(g.Cols())
+	currentCell, err := g.CellAt(c, r)
+	if err != nil {
+		return fmt.Errorf("init: could not get first cell: %v", err)
+	}
+
+	inMaze := newCellSet(g)
+	frontier := newCellSet(g)
+	inMaze.add(currentCell)
+	addFrontier(currentCell, inMaze, frontier)
+
+	for frontier.len() > 0 {
+		currentCell = frontier.random(s.rng)
+		frontier.remove(currentCell)
+
+		currentCell.Link(randomNeighbourIn(currentCell, inMaze, s.rng))
+		inMaze.add(currentCell)
+		addFrontier(currentCell, inMaze, frontier)
+	}
+
+	return nil
+}
+
+// TruePrim initializes a grid into a maze using randomized Prim's
+// algorithm, where each cell is given a random weight and the frontier
+// cell with the lowest weight is added to the maze next.
+type TruePrim struct {
+	rng *rand.Rand
+}
+
+// NewTruePrim returns a new TruePrim
+func NewTruePrim(seed int64) Initer {
+	return &TruePrim{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Init initializes a grid into a maze using the true Prim's algorithm
+func (t *TruePrim) Init(g *Grid) error {
+	weights := make([]float64, g.Len())
+	for i := range weights {
+		weights[i] = t.rng.Float64()
+	}
+
+	// Choose a random starting cell
+	r := t.rng.Intn(g.Rows())
+	c := t.rng.Intn(g.Cols())
+	currentCell, err := g.CellAt(c, r)
+	if err != nil {
+		return fmt.Errorf("init: could not get first cell: %v", err)
+	}
+
+	inMaze := newCellSet(g)
+	inFrontier := make([]bool, g.Len())
+	frontier := &weightedCells{g: g, weights: weights}
+
+	inMaze.add(currentCell)
+	t.pushFrontier(currentCell, inMaze, inFrontier, frontier)
+
+	for frontier.Len() > 0 {
+		currentCell = heap.Pop(frontier).(*Cell)
+
+		currentCell.Link(randomNeighbourIn(currentCell, inMaze, t.rng))
+		inMaze.add(currentCell)
+		t.pushFrontier(currentCell, inMaze, inFrontier, frontier)
+	}
+
+	return nil
+}
+
+// pushFrontier pushes all neighbours of c which are not in the maze
+// and not yet in the frontier onto the frontier
+func (t *TruePrim) pushFrontier(c *Cell, inMaze *cellSet, inFrontier []bool,
+	frontier *weightedCells) {
+	for _, neighbour := range c.Neighbours() {
+		if neighbour == nil || inMaze.has(neighbour) {
+			continue
+		}
+
+		index := frontier.g.Index(neighbour.Col(), neighbour.Row())
+		if !inFrontier[index] {
+			inFrontier[index] = true
+			heap.Push(frontier, neighbour)
+		}
+	}
+}
+
+// weightedCells implements a min-heap of cells ordered by weight
+type weightedCells struct {
+	cells   []*Cell
+	weights []float64 // Weight of each cell, by grid index
+	g       *Grid
+}
+
+func (w *weightedCells) Len() int { return len(w.cells) }
+
+func (w *weightedCells) Less(i, j int) bool {
+	ci, cj := w.cells[i], w.cells[j]
+	return w.weights[w.g.Index(ci.Col(), ci.Row())] <
+		w.weights[w.g.Index(cj.Col(), cj.Row())]
+}
+
+func (w *weightedCells) Swap(i, j int) {
+	w.cells[i], w.cells[j] = w.cells[j], w.cells[i]
+}
+
+func (w *weightedCells) Push(x interface{}) {
+	w.cells = append(w.cells, x.(*Cell))
+}
+
+func (w *weightedCells) Pop() interface{} {
+	last := w.cells[len(w.cells)-1]
+	w.cells = w.cells[:len(w.cells)-1]
+	return last
+}
+
+// addFrontier adds all neighbours of c which are not in the maze to
+// the frontier
+func addFrontier(c *Cell, inMaze, frontier *cellSet) {
+	for _, neighbour := range c.Neighbours() {
+		if neighbour != nil && !inMaze.has(neighbour) {
+			frontier.add(neighbour)
+		}
+	}
+}
+
+// randomNeighbourIn returns a random neighbour of c which is in set.
+// At least one neighbour of c must be in set.
+func randomNeighbourIn(c *Cell, set *cellSet, rng *rand.Rand) *Cell {
+	neighbours := make([]*Cell, 0, 4)
+	for _, neighbour := range c.Neighbours() {
+		if neighbour != nil && set.has(neighbour) {
+			neighbours = append(neighbours, neighbour)
+		}
+	}
+
+	return neighbours[rng.Intn(len(neighbours))]
+}
@@ -0,0 +1,131 @@
+package gomaze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RecursiveDivision initializes a grid into a maze using the recursive
+// division algorithm. Unlike the other Initers, which carve passages
+// into a grid of walled cells, recursive division first removes all
+// walls from the grid and then adds walls, recursively dividing the
+// grid into smaller chambers, each with a single passage through the
+// dividing wall.
+type RecursiveDivision struct {
+	rng *rand.Rand
+
+	// minSize is the minimum size of a chamber. Chambers with a width
+	// or height of at most minSize are not divided further. If
+	// minSize is 1, then the resulting maze is perfect, otherwise
+	// open rooms are left in the maze.
+	minSize int
+
+	// horizontalBias weights the choice of dividing a chamber with a
+	// horizontal wall rather than a vertical wall. Chambers are divided
+	// horizontally when horizontalBias * height exceeds
+	// (1 - horizontalBias) * width, vertically when it is less, and
+	// horizontally with probability horizontalBias otherwise. A value
+	// of 0.5 divides chambers across their longest dimension.
+	horizontalBias float64
+}
+
+// NewRecursiveDivision returns a new RecursiveDivision which generates
+// perfect mazes with no bias towards horizontal or vertical walls
+func NewRecursiveDivision(seed int64) Initer {
+	return &RecursiveDivision{
+		rng:            rand.New(rand.NewSource(seed)),
+		minSize:        1,
+		horizontalBias: 0.5,
+	}
+}
+
+// NewRecursiveDivisionWithOptions returns a new RecursiveDivision
+// which does not divide chambers with a width or height of at most
+// minSize, and which is biased towards horizontal walls by
+// horizontalBias ∈ [0, 1]. A bias of 0.5 favours neither horizontal
+// nor vertical walls.
+func NewRecursiveDivisionWithOptions(seed int64, minSize int,
+	horizontalBias float64) (Initer, error) {
+	if minSize < 1 {
+		return nil, fmt.Errorf("newRecursiveDivisionWithOptions: minimum "+
+			"chamber size must be positive but got %v", minSize)
+	}
+	if horizontalBias < 0 || horizontalBias > 1 {
+		return nil, fmt.Errorf("newRecursiveDivisionWithOptions: "+
+			"horizontal bias %v ∉ [0, 1]", horizontalBias)
+	}
+
+	return &RecursiveDivision{
+		rng:            rand.New(rand.NewSource(seed)),
+		minSize:        minSize,
+		horizontalBias: horizontalBias,
+	}, nil
+}
+
+// Init initializes a grid into a maze using the recursive division
+// algorithm
+func (d *RecursiveDivision) Init(g *Grid) error {
+	// Remove all walls from the grid
+	for _, cell := range g.Cells() {
+		if cell.South() != nil {
+			cell.Link(cell.South())
+		}
+		if cell.East() != nil {
+			cell.Link(cell.East())
+		}
+	}
+
+	d.divide(g, 0, 0, g.Rows(), g.Cols())
+	return nil
+}
+
+// divide recursively divides the chamber with top left cell at row
+// and col and of dimensions height ⨉ width
+func (d *RecursiveDivision) divide(g *Grid, row, col, height, width int) {
+	if height <= d.minSize || width <= d.minSize {
+		return
+	}
+
+	h := d.horizontalBias * float64(height)
+	w := (1 - d.horizontalBias) * float64(width)
+
+	var horizontal bool
+	switch {
+	case h > w:
+		horizontal = true
+
+	case h < w:
+		horizontal = false
+
+	default:
+		horizontal = d.rng.Float64() < d.horizontalBias
+	}
+
+	if horizontal {
+		// Add a wall to the south of divRow with a single passage
+		divRow := row + d.rng.Intn(height-1)
+		passage := col + d.rng.Intn(width)
+		for c := col; c < col+width; c++ {
+			if c != passage {
+				cell := g.cells[g.Index(c, divRow)]
+				cell.Unlink(cell.South())
+			}
+		}
+
+		d.divide(g, row, col, divRow-row+1, width)
+		d.divide(g, divRow+1, col, row+height-divRow-1, width)
+	} else {
+		// Add a wall to the east of divCol with a single passage
+		divCol := col + d.rng.Intn(width-1)
+		passage := row + d.rng.Intn(height)
+		for r := row; r < row+height; r++ {
+			if r != passage {
+				cell := g.cells[g.Index(divCol, r)]
+				cell.Unlink(cell.East())
+			}
+		}
+
+		d.divide(g, row, col, height, divCol-col+1)
+		d.divide(g, row, divCol+1, height, col+width-divCol-1)
+	}
+}
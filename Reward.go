@@ -0,0 +1,155 @@
+package gomaze
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rewarder determines the rewards given to a player in a maze. Given
+// the cell prev that the player moved from, the action actually taken
+// (after any slipping), the cell next that the player moved to, and
+// whether the player stayed in prev because it moved into a wall,
+// Reward returns the reward for the transition. The goal of the maze
+// can be found with Maze.Goal.
+type Rewarder interface {
+	Reward(m *Maze, prev *Cell, action int, next *Cell, hitWall bool) float64
+}
+
+// StepCostReward gives a reward of -cost on each step which does not
+// reach the goal, and a reward of 0 on reaching the goal
+type StepCostReward struct {
+	cost float64
+}
+
+// NewStepCostReward returns a new StepCostReward. A StepCostReward
+// with a cost of 1 is used by mazes by default.
+func NewStepCostReward(cost float64) Rewarder {
+	return &StepCostReward{cost: cost}
+}
+
+// Reward returns the reward for a transition
+func (s *StepCostReward) Reward(m *Maze, prev *Cell, action int, next *Cell,
+	hitWall bool) float64 {
+	if next == m.goal {
+		return 0.0
+	}
+	return -s.cost
+}
+
+// SparseReward gives a reward of +1 on reaching the goal and a reward
+// of 0 otherwise
+type SparseReward struct{}
+
+// NewSparseReward returns a new SparseReward
+func NewSparseReward() Rewarder {
+	return &SparseReward{}
+}
+
+// Reward returns the reward for a transition
+func (s *SparseReward) Reward(m *Maze, prev *Cell, action int, next *Cell,
+	hitWall bool) float64 {
+	if next == m.goal {
+		return 1.0
+	}
+	return 0.0
+}
+
+// WallPenaltyReward adds a penalty to the rewards of another Rewarder
+// whenever the player moves into a wall
+type WallPenaltyReward struct {
+	base    Rewarder
+	penalty float64
+}
+
+// NewWallPenaltyReward returns a new WallPenaltyReward which gives the
+// rewards of base, minus penalty whenever the player moves into a wall
+func NewWallPenaltyReward(base Rewarder, penalty float64) (Rewarder, error) {
+	if base == nil {
+		return nil, fmt.Errorf("newWallPenaltyReward: base rewarder must " +
+			"be non-nil")
+	}
+
+	return &WallPenaltyReward{
+		base:    base,
+		penalty: penalty,
+	}, nil
+}
+
+// Reward returns the reward for a transition
+func (w *WallPenaltyReward) Reward(m *Maze, prev *Cell, action int,
+	next *Cell, hitWall bool) float64 {
+	reward := w.base.Reward(m, prev, action, next, hitWall)
+	if hitWall {
+		reward -= w.penalty
+	}
+	return reward
+}
+
+// PotentialShapingReward adds potential-based shaping to the rewards
+// of another Rewarder. The potential of a cell is the negative length
+// of the shortest path from the cell to the goal, and the shaping
+// reward for a transition from prev to next is
+// discount * potential(next) - potential(prev). Potential-based
+// shaping does not change the optimal policy of the maze.
+type PotentialShapingReward struct {
+	base     Rewarder
+	discount float64
+
+	// Distances to the goal, which are recomputed whenever the goal
+	// changes
+	goal  *Cell
+	dists *Distances
+}
+
+// NewPotentialShapingReward returns a new PotentialShapingReward which
+// gives the rewards of base plus a shaping reward for the given
+// discount factor
+func NewPotentialShapingReward(base Rewarder, discount float64) (Rewarder,
+	error) {
+	if base == nil {
+		return nil, fmt.Errorf("newPotentialShapingReward: base rewarder " +
+			"must be non-nil")
+	}
+	if discount < 0 || discount > 1 || math.IsNaN(discount) {
+		return nil, fmt.Errorf("newPotentialShapingReward: discount %v ∉ "+
+			"[0, 1]", discount)
+	}
+
+	return &PotentialShapingReward{
+		base:     base,
+		discount: discount,
+	}, nil
+}
+
+// Reward returns the reward for a transition
+func (p *PotentialShapingReward) Reward(m *Maze, prev *Cell, action int,
+	next *Cell, hitWall bool) float64 {
+	reward := p.base.Reward(m, prev, action, next, hitWall)
+	return reward + p.discount*p.potential(m, next) - p.potential(m, prev)
+}
+
+// potential returns the potential of cell c in maze m
+func (p *PotentialShapingReward) potential(m *Maze, c *Cell) float64 {
+	if p.goal != m.goal {
+		p.goal = m.goal
+		p.dists = NewDistances(m.goal)
+	}
+
+	dist, ok := p.dists.Distance(c)
+	if !ok {
+		// The goal cannot be reached from c
+		return 0.0
+	}
+	return -float64(dist)
+}
+
+// SetRewarder sets the Rewarder used to compute the rewards of the
+// maze in Step, MDP, and OptimalValues
+func (m *Maze) SetRewarder(r Rewarder) error {
+	if r == nil {
+		return fmt.Errorf("setRewarder: rewarder must be non-nil")
+	}
+
+	m.rewarder = r
+	return nil
+}
@@ -0,0 +1,18 @@
+package gomaze
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewPotentialShapingRewardInvalid(t *testing.T) {
+	for _, discount := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err := NewPotentialShapingReward(NewSparseReward(), discount)
+		if err == nil {
+			t.Errorf("expected error for discount %v", discount)
+		}
+	}
+	if _, err := NewPotentialShapingReward(nil, 0.9); err == nil {
+		t.Errorf("expected error for nil base rewarder")
+	}
+}
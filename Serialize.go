@@ -0,0 +1,844 @@
+package gomaze
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// Wall bits of serialized cells. A bit is set if the cell has a wall
+// on the corresponding side.
+const (
+	northWallBit byte = 1 << iota
+	southWallBit
+	westWallBit
+	eastWallBit
+)
+
+// Flag bits of serialized mazes
+const (
+	oneHotStateFlag byte = 1 << iota
+	sensorPositionFlag
+	sensorGoalFlag
+	slipFlag
+	placementFlag
+	resampleFlag
+	stepInfoFlag
+)
+
+// Binary format headers and version
+const (
+	gridMagic     = "GMZG"
+	mazeMagic     = "GMZM"
+	binaryVersion = 1
+)
+
+// maxGridCells is the maximum number of cells of deserialized grids
+const maxGridCells int = 1 << 30
+
+// maxRewarderDepth is the maximum nesting depth of deserialized
+// Rewarders, such as a WallPenaltyReward wrapping a
+// PotentialShapingReward wrapping a StepCostReward
+const maxRewarderDepth int = 32
+
+// Types of serialized Rewarders
+const (
+	stepCostType         = "stepCost"
+	sparseType           = "sparse"
+	wallPenaltyType      = "wallPenalty"
+	potentialShapingType = "potentialShaping"
+)
+
+// rewarderTypes are the types of serialized Rewarders, indexed by
+// their binary representation
+var rewarderTypes = []string{
+	stepCostType,
+	sparseType,
+	wallPenaltyType,
+	potentialShapingType,
+}
+
+// gridJSON is the JSON representation of a Grid
+type gridJSON struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+
+	// Walls holds the wall bits of each cell, indexed as in Grid.Index
+	Walls []byte `json:"walls"`
+}
+
+// mazeJSON is the JSON representation of a Maze
+type mazeJSON struct {
+	Grid   *Grid  `json:"grid"`
+	Start  [2]int `json:"start"`  // (x, y) position of the start
+	Goal   [2]int `json:"goal"`   // (x, y) position of the goal
+	Player [2]int `json:"player"` // (x, y) position of the player
+
+	OneHotState    bool    `json:"oneHotState"`
+	ObsType        ObsType `json:"obsType"`
+	Window         int     `json:"window,omitempty"`
+	SensorPosition bool    `json:"sensorPosition,omitempty"`
+	SensorGoal     bool    `json:"sensorGoal,omitempty"`
+	Scale          int     `json:"scale,omitempty"`
+	MaxSteps       int     `json:"maxSteps,omitempty"`
+	Steps          int     `json:"steps,omitempty"`
+	StepInfo       bool    `json:"stepInfo,omitempty"`
+
+	Slip      *slipJSON      `json:"slip,omitempty"`
+	Placement *placementJSON `json:"placement,omitempty"`
+
+	// Rewarder is the Rewarder of the maze. If nil, the default
+	// Rewarder is used.
+	Rewarder *rewarderJSON `json:"rewarder,omitempty"`
+}
+
+// slipJSON is the JSON representation of the slipping of a Maze
+type slipJSON struct {
+	Prob float64  `json:"prob"`
+	Mode SlipMode `json:"mode"`
+	Seed int64    `json:"seed"`
+}
+
+// placementJSON is the JSON representation of the random start and
+// goal placement of a Maze
+type placementJSON struct {
+	MinDist  int   `json:"minDist"`
+	MaxDist  int   `json:"maxDist"`
+	Resample bool  `json:"resample"`
+	Seed     int64 `json:"seed"`
+}
+
+// rewarderJSON is the JSON representation of a Rewarder. Only the
+// Rewarders of this package can be represented.
+type rewarderJSON struct {
+	Type     string        `json:"type"`
+	Cost     float64       `json:"cost,omitempty"`
+	Penalty  float64       `json:"penalty,omitempty"`
+	Discount float64       `json:"discount,omitempty"`
+	Base     *rewarderJSON `json:"base,omitempty"`
+}
+
+// walls returns the wall bits of each cell in the grid
+func (g *Grid) walls() []byte {
+	walls := make([]byte, g.Len())
+	for i, cell := range g.cells {
+		if !cell.CanMoveNorth() {
+			walls[i] |= northWallBit
+		}
+		if !cell.CanMoveSouth() {
+			walls[i] |= southWallBit
+		}
+		if !cell.CanMoveWest() {
+			walls[i] |= westWallBit
+		}
+		if !cell.CanMoveEast() {
+			walls[i] |= eastWallBit
+		}
+	}
+	return walls
+}
+
+// gridFromWalls returns a new grid of dimensions rows ⨉ cols whose
+// cells have the given wall bits
+func gridFromWalls(rows, cols int, walls []byte) (*Grid, error) {
+	// Check the dimensions without overflowing before allocating any
+	// cells
+	if rows <= 0 || cols <= 0 || rows > maxGridCells/cols {
+		return nil, fmt.Errorf("gridFromWalls: invalid dimensions %v ⨉ %v",
+			rows, cols)
+	}
+	if len(walls) != rows*cols {
+		return nil, fmt.Errorf("gridFromWalls: expected walls for %v cells "+
+			"but got %v", rows*cols, len(walls))
+	}
+
+	g := NewGrid(rows, cols)
+	for i, cell := range g.cells {
+		wall := walls[i]
+
+		// Walls between neighbours must be consistent on both sides,
+		// and the boundary of the grid must be walled
+		if cell.North() == nil && wall&northWallBit == 0 ||
+			cell.West() == nil && wall&westWallBit == 0 {
+			return nil, fmt.Errorf("gridFromWalls: cell (%v, %v) is open to "+
+				"the boundary", cell.Col(), cell.Row())
+		}
+		if cell.South() == nil && wall&southWallBit == 0 ||
+			cell.East() == nil && wall&eastWallBit == 0 {
+			return nil, fmt.Errorf("gridFromWalls: cell (%v, %v) is open to "+
+				"the boundary", cell.Col(), cell.Row())
+		}
+		if cell.South() != nil {
+			south := walls[g.Index(cell.Col(), cell.Row()+1)]
+			if (wall&southWallBit == 0) != (south&northWallBit == 0) {
+				return nil, fmt.Errorf("gridFromWalls: inconsistent wall "+
+					"between (%v, %v) and its southern neighbour", cell.Col(),
+					cell.Row())
+			}
+			if wall&southWallBit == 0 {
+				cell.Link(cell.South())
+			}
+		}
+		if cell.East() != nil {
+			east := walls[i+1]
+			if (wall&eastWallBit == 0) != (east&westWallBit == 0) {
+				return nil, fmt.Errorf("gridFromWalls: inconsistent wall "+
+					"between (%v, %v) and its eastern neighbour", cell.Col(),
+					cell.Row())
+			}
+			if wall&eastWallBit == 0 {
+				cell.Link(cell.East())
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// set replaces g with other
+func (g *Grid) set(other *Grid) {
+	*g = *other
+	for _, cell := range g.cells {
+		cell.grid = g
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface. The walls of
+// each cell are stored as a base64-encoded byte per cell.
+func (g *Grid) MarshalJSON() ([]byte, error) {
+	return json.Marshal(gridJSON{
+		Rows:  g.rows,
+		Cols:  g.cols,
+		Walls: g.walls(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. If an error
+// is returned, the grid is left unchanged.
+func (g *Grid) UnmarshalJSON(data []byte) error {
+	var v gridJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("unmarshalJSON: %v", err)
+	}
+
+	grid, err := gridFromWalls(v.Rows, v.Cols, v.Walls)
+	if err != nil {
+		return fmt.Errorf("unmarshalJSON: %v", err)
+	}
+
+	g.set(grid)
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// walls of two cells are packed into each byte.
+func (g *Grid) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(gridMagic)
+	g.writeBinary(&buf)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// If an error is returned, the grid is left unchanged.
+func (g *Grid) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if err := readMagic(r, gridMagic); err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+
+	grid, err := readGrid(r)
+	if err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("unmarshalBinary: %v unexpected trailing bytes",
+			r.Len())
+	}
+
+	g.set(grid)
+	return nil
+}
+
+// writeBinary writes the binary representation of the grid, without a
+// header, to buf
+func (g *Grid) writeBinary(buf *bytes.Buffer) {
+	buf.WriteByte(binaryVersion)
+	writeUvarint(buf, uint64(g.rows))
+	writeUvarint(buf, uint64(g.cols))
+
+	walls := g.walls()
+	for i := 0; i < len(walls); i += 2 {
+		packed := walls[i]
+		if i+1 < len(walls) {
+			packed |= walls[i+1] << 4
+		}
+		buf.WriteByte(packed)
+	}
+}
+
+// readGrid reads the binary representation of a grid, without a
+// header, from r
+func readGrid(r *bytes.Reader) (*Grid, error) {
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("readGrid: could not read version: %v", err)
+	}
+	if version != binaryVersion {
+		return nil, fmt.Errorf("readGrid: unsupported version %v", version)
+	}
+
+	rows, err := readInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("readGrid: could not read rows: %v", err)
+	}
+	cols, err := readInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("readGrid: could not read columns: %v", err)
+	}
+
+	// Check the size of the grid against the remaining data before
+	// allocating any walls
+	if rows <= 0 || cols <= 0 || rows > maxGridCells/cols ||
+		(rows*cols+1)/2 > r.Len() {
+		return nil, fmt.Errorf("readGrid: invalid dimensions %v ⨉ %v for %v "+
+			"bytes of walls", rows, cols, r.Len())
+	}
+
+	n := rows * cols
+	packed := make([]byte, (n+1)/2)
+	if _, err := io.ReadFull(r, packed); err != nil {
+		return nil, fmt.Errorf("readGrid: could not read walls: %v", err)
+	}
+
+	walls := make([]byte, n)
+	for i := range walls {
+		walls[i] = (packed[i/2] >> (4 * (i % 2))) & 0xf
+	}
+
+	g, err := gridFromWalls(rows, cols, walls)
+	if err != nil {
+		return nil, fmt.Errorf("readGrid: %v", err)
+	}
+	return g, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. Along with the
+// grid, the start, goal, and player positions, the observation,
+// episode length, and step information settings, slipping, random
+// start and goal placement, and the Rewarder are stored. Random number
+// generators are stored as the seed they were last seeded with, so
+// that they restart from that seed when unmarshalled. An error is
+// returned if the maze uses a Rewarder not defined in this package.
+// Any attached GIFRecorder is not stored.
+func (m *Maze) MarshalJSON() ([]byte, error) {
+	v, err := m.toJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshalJSON: %v", err)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. If an error
+// is returned, the maze is left unchanged.
+func (m *Maze) UnmarshalJSON(data []byte) error {
+	var v mazeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("unmarshalJSON: %v", err)
+	}
+
+	if err := m.fromJSON(v); err != nil {
+		return fmt.Errorf("unmarshalJSON: %v", err)
+	}
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// same information is stored as by MarshalJSON.
+func (m *Maze) MarshalBinary() ([]byte, error) {
+	v, err := m.toJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshalBinary: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(mazeMagic)
+	m.Grid.writeBinary(&buf)
+
+	for _, pos := range [][2]int{v.Start, v.Goal, v.Player} {
+		writeUvarint(&buf, uint64(m.Index(pos[0], pos[1])))
+	}
+
+	var flags byte
+	for _, flag := range []struct {
+		set bool
+		bit byte
+	}{
+		{v.OneHotState, oneHotStateFlag},
+		{v.SensorPosition, sensorPositionFlag},
+		{v.SensorGoal, sensorGoalFlag},
+		{v.Slip != nil, slipFlag},
+		{v.Placement != nil, placementFlag},
+		{v.Placement != nil && v.Placement.Resample, resampleFlag},
+		{v.StepInfo, stepInfoFlag},
+	} {
+		if flag.set {
+			flags |= flag.bit
+		}
+	}
+	buf.WriteByte(flags)
+
+	for _, value := range []int{int(v.ObsType), v.Window, v.Scale,
+		v.MaxSteps, v.Steps} {
+		writeUvarint(&buf, uint64(value))
+	}
+
+	if v.Slip != nil {
+		writeFloat(&buf, v.Slip.Prob)
+		writeUvarint(&buf, uint64(v.Slip.Mode))
+		writeVarint(&buf, v.Slip.Seed)
+	}
+	if v.Placement != nil {
+		writeUvarint(&buf, uint64(v.Placement.MinDist))
+		writeUvarint(&buf, uint64(v.Placement.MaxDist))
+		writeVarint(&buf, v.Placement.Seed)
+	}
+	v.Rewarder.writeBinary(&buf)
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// If an error is returned, the maze is left unchanged.
+func (m *Maze) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if err := readMagic(r, mazeMagic); err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+
+	grid, err := readGrid(r)
+	if err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+	v := mazeJSON{Grid: grid}
+
+	for _, pos := range []*[2]int{&v.Start, &v.Goal, &v.Player} {
+		index, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read position: %v",
+				err)
+		}
+		if index >= uint64(v.Grid.Len()) {
+			return fmt.Errorf("unmarshalBinary: position index %v out of "+
+				"range with length %v", index, v.Grid.Len())
+		}
+		cell := v.Grid.cells[index]
+		*pos = [2]int{cell.Col(), cell.Row()}
+	}
+
+	flags, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("unmarshalBinary: could not read flags: %v", err)
+	}
+	v.OneHotState = flags&oneHotStateFlag != 0
+	v.SensorPosition = flags&sensorPositionFlag != 0
+	v.SensorGoal = flags&sensorGoalFlag != 0
+	v.StepInfo = flags&stepInfoFlag != 0
+
+	values := make([]int, 5)
+	for i := range values {
+		if values[i], err = readInt(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read settings: %v",
+				err)
+		}
+	}
+	v.ObsType = ObsType(values[0])
+	v.Window, v.Scale, v.MaxSteps, v.Steps = values[1], values[2], values[3],
+		values[4]
+
+	if flags&slipFlag != 0 {
+		v.Slip = &slipJSON{}
+		if v.Slip.Prob, err = readFloat(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read slip "+
+				"probability: %v", err)
+		}
+		mode, err := readInt(r)
+		if err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read slip mode: %v",
+				err)
+		}
+		v.Slip.Mode = SlipMode(mode)
+		if v.Slip.Seed, err = binary.ReadVarint(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read slip seed: %v",
+				err)
+		}
+	}
+
+	if flags&placementFlag != 0 {
+		v.Placement = &placementJSON{Resample: flags&resampleFlag != 0}
+		if v.Placement.MinDist, err = readInt(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read placement: %v",
+				err)
+		}
+		if v.Placement.MaxDist, err = readInt(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read placement: %v",
+				err)
+		}
+		if v.Placement.Seed, err = binary.ReadVarint(r); err != nil {
+			return fmt.Errorf("unmarshalBinary: could not read placement: %v",
+				err)
+		}
+	}
+
+	if v.Rewarder, err = readRewarder(r, 0); err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+
+	if r.Len() != 0 {
+		return fmt.Errorf("unmarshalBinary: %v unexpected trailing bytes",
+			r.Len())
+	}
+
+	if err := m.fromJSON(v); err != nil {
+		return fmt.Errorf("unmarshalBinary: %v", err)
+	}
+	return nil
+}
+
+// toJSON returns the JSON representation of the maze
+func (m *Maze) toJSON() (mazeJSON, error) {
+	position := func(c *Cell) [2]int {
+		return [2]int{c.Col(), c.Row()}
+	}
+
+	rewarder, err := newRewarderJSON(m.rewarder, 0)
+	if err != nil {
+		return mazeJSON{}, fmt.Errorf("toJSON: %v", err)
+	}
+
+	v := mazeJSON{
+		Grid:           m.Grid,
+		Start:          position(m.start),
+		Goal:           position(m.goal),
+		Player:         position(m.player.in),
+		OneHotState:    m.oneHotState,
+		ObsType:        m.obsType,
+		Window:         m.window,
+		SensorPosition: m.sensorPosition,
+		SensorGoal:     m.sensorGoal,
+		Scale:          m.scale,
+		MaxSteps:       m.maxSteps,
+		Steps:          m.steps,
+		StepInfo:       m.stepInfo,
+		Rewarder:       rewarder,
+	}
+
+	if m.slip != nil {
+		v.Slip = &slipJSON{
+			Prob: m.slip.prob,
+			Mode: m.slip.mode,
+			Seed: m.slip.seed,
+		}
+	}
+	if m.placement != nil {
+		v.Placement = &placementJSON{
+			MinDist:  m.placement.minDist,
+			MaxDist:  m.placement.maxDist,
+			Resample: m.placement.resample,
+			Seed:     m.placement.seed,
+		}
+	}
+
+	return v, nil
+}
+
+// fromJSON replaces the maze with the maze represented by v. If an
+// error is returned, the maze is left unchanged.
+func (m *Maze) fromJSON(v mazeJSON) error {
+	if v.Grid == nil || v.Grid.Len() == 0 {
+		return fmt.Errorf("fromJSON: missing grid")
+	}
+
+	cells := make([]*Cell, 3)
+	for i, pos := range [][2]int{v.Start, v.Goal, v.Player} {
+		cell, err := v.Grid.CellAt(pos[0], pos[1])
+		if err != nil {
+			return fmt.Errorf("fromJSON: invalid position: %v", err)
+		}
+		cells[i] = cell
+	}
+
+	switch v.ObsType {
+	case FullObs, PixelObs:
+
+	case EgocentricObs:
+		if v.Window < 1 || v.Window%2 == 0 {
+			return fmt.Errorf("fromJSON: invalid window size %v", v.Window)
+		}
+
+	case WallSensorObs:
+
+	case RGBObs:
+		if v.Scale < 1 {
+			return fmt.Errorf("fromJSON: invalid scale %v", v.Scale)
+		}
+
+	default:
+		return fmt.Errorf("fromJSON: unknown observation type %v", v.ObsType)
+	}
+
+	if v.MaxSteps < 0 || v.Steps < 0 {
+		return fmt.Errorf("fromJSON: invalid episode length settings")
+	}
+
+	maze := newMaze(v.Grid, cells[0], cells[1], v.OneHotState)
+	maze.player.in = cells[2]
+	maze.obsType = v.ObsType
+	maze.window = v.Window
+	maze.sensorPosition = v.SensorPosition
+	maze.sensorGoal = v.SensorGoal
+	maze.scale = v.Scale
+	maze.maxSteps = v.MaxSteps
+	maze.steps = v.Steps
+	maze.stepInfo = v.StepInfo
+
+	if v.Slip != nil {
+		err := maze.SetSlip(v.Slip.Prob, v.Slip.Mode, v.Slip.Seed)
+		if err != nil {
+			return fmt.Errorf("fromJSON: %v", err)
+		}
+	}
+
+	if p := v.Placement; p != nil {
+		if p.MinDist < 1 || p.MaxDist < p.MinDist {
+			return fmt.Errorf("fromJSON: invalid distance range [%v, %v]",
+				p.MinDist, p.MaxDist)
+		}
+		maze.placement = &placement{
+			rng:      rand.New(rand.NewSource(p.Seed)),
+			seed:     p.Seed,
+			minDist:  p.MinDist,
+			maxDist:  p.MaxDist,
+			resample: p.Resample,
+		}
+	}
+
+	if v.Rewarder != nil {
+		rewarder, err := v.Rewarder.rewarder(0)
+		if err != nil {
+			return fmt.Errorf("fromJSON: %v", err)
+		}
+		maze.rewarder = rewarder
+	}
+
+	*m = *maze
+	return nil
+}
+
+// newRewarderJSON returns the JSON representation of r, which is
+// nested depth levels deep in another Rewarder
+func newRewarderJSON(r Rewarder, depth int) (*rewarderJSON, error) {
+	if depth >= maxRewarderDepth {
+		return nil, fmt.Errorf("newRewarderJSON: rewarders nested more than "+
+			"%v levels deep", maxRewarderDepth)
+	}
+
+	var (
+		v    *rewarderJSON
+		base Rewarder
+	)
+	switch r := r.(type) {
+	case *StepCostReward:
+		v = &rewarderJSON{Type: stepCostType, Cost: r.cost}
+
+	case *SparseReward:
+		v = &rewarderJSON{Type: sparseType}
+
+	case *WallPenaltyReward:
+		v = &rewarderJSON{Type: wallPenaltyType, Penalty: r.penalty}
+		base = r.base
+
+	case *PotentialShapingReward:
+		v = &rewarderJSON{Type: potentialShapingType, Discount: r.discount}
+		base = r.base
+
+	default:
+		return nil, fmt.Errorf("newRewarderJSON: cannot serialize rewarder "+
+			"of type %T", r)
+	}
+
+	if base != nil {
+		b, err := newRewarderJSON(base, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		v.Base = b
+	}
+	return v, nil
+}
+
+// rewarder returns the Rewarder represented by v, which is nested
+// depth levels deep in another Rewarder
+func (v *rewarderJSON) rewarder(depth int) (Rewarder, error) {
+	if depth >= maxRewarderDepth {
+		return nil, fmt.Errorf("rewarder: rewarders nested more than %v "+
+			"levels deep", maxRewarderDepth)
+	}
+
+	switch v.Type {
+	case stepCostType:
+		return NewStepCostReward(v.Cost), nil
+
+	case sparseType:
+		return NewSparseReward(), nil
+
+	case wallPenaltyType, potentialShapingType:
+		if v.Base == nil {
+			return nil, fmt.Errorf("rewarder: missing base of %v rewarder",
+				v.Type)
+		}
+		base, err := v.Base.rewarder(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.Type == wallPenaltyType {
+			return NewWallPenaltyReward(base, v.Penalty)
+		}
+		return NewPotentialShapingReward(base, v.Discount)
+
+	default:
+		return nil, fmt.Errorf("rewarder: unknown rewarder type %q", v.Type)
+	}
+}
+
+// writeBinary writes the binary representation of the Rewarder
+// represented by v to buf
+func (v *rewarderJSON) writeBinary(buf *bytes.Buffer) {
+	for i, t := range rewarderTypes {
+		if t == v.Type {
+			buf.WriteByte(byte(i))
+		}
+	}
+
+	switch v.Type {
+	case stepCostType:
+		writeFloat(buf, v.Cost)
+
+	case wallPenaltyType:
+		writeFloat(buf, v.Penalty)
+		v.Base.writeBinary(buf)
+
+	case potentialShapingType:
+		writeFloat(buf, v.Discount)
+		v.Base.writeBinary(buf)
+	}
+}
+
+// readRewarder reads the binary representation of a Rewarder, which is
+// nested depth levels deep in another Rewarder, from r
+func readRewarder(r *bytes.Reader, depth int) (*rewarderJSON, error) {
+	if depth >= maxRewarderDepth {
+		return nil, fmt.Errorf("readRewarder: rewarders nested more than %v "+
+			"levels deep", maxRewarderDepth)
+	}
+
+	t, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("readRewarder: could not read type: %v", err)
+	}
+	if int(t) >= len(rewarderTypes) {
+		return nil, fmt.Errorf("readRewarder: unknown rewarder type %v", t)
+	}
+	v := &rewarderJSON{Type: rewarderTypes[t]}
+
+	var param *float64
+	switch v.Type {
+	case stepCostType:
+		param = &v.Cost
+
+	case wallPenaltyType:
+		param = &v.Penalty
+
+	case potentialShapingType:
+		param = &v.Discount
+	}
+	if param != nil {
+		if *param, err = readFloat(r); err != nil {
+			return nil, fmt.Errorf("readRewarder: could not read %v "+
+				"rewarder: %v", v.Type, err)
+		}
+	}
+
+	if v.Type == wallPenaltyType || v.Type == potentialShapingType {
+		if v.Base, err = readRewarder(r, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// writeUvarint writes x to buf as a variable-length unsigned integer
+func writeUvarint(buf *bytes.Buffer, x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], x)
+	buf.Write(b[:n])
+}
+
+// writeVarint writes x to buf as a variable-length signed integer
+func writeVarint(buf *bytes.Buffer, x int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], x)
+	buf.Write(b[:n])
+}
+
+// writeFloat writes x to buf as 8 little-endian bytes
+func writeFloat(buf *bytes.Buffer, x float64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(x))
+	buf.Write(b[:])
+}
+
+// readInt reads a non-negative int written by writeUvarint from r
+func readInt(r *bytes.Reader) (int, error) {
+	x, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if x > math.MaxInt {
+		return 0, fmt.Errorf("readInt: value %v overflows int", x)
+	}
+	return int(x), nil
+}
+
+// readFloat reads a float64 written by writeFloat from r. Since none
+// of the serialized values of mazes may be NaN or infinite, an error is
+// returned for such values.
+func readFloat(r *bytes.Reader) (float64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+
+	x := math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, fmt.Errorf("readFloat: value %v is not finite", x)
+	}
+	return x, nil
+}
+
+// readMagic reads and checks the header magic from r
+func readMagic(r *bytes.Reader, magic string) error {
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("readMagic: could not read header: %v", err)
+	}
+	if string(header) != magic {
+		return fmt.Errorf("readMagic: invalid header %q", header)
+	}
+	return nil
+}
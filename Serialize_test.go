@@ -0,0 +1,295 @@
+package gomaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// codec marshals and unmarshals values in one serialization format
+type codec struct {
+	name      string
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+var codecs = []codec{
+	{
+		name:      "JSON",
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	},
+	{
+		name: "binary",
+		marshal: func(v interface{}) ([]byte, error) {
+			switch v := v.(type) {
+			case *Grid:
+				return v.MarshalBinary()
+			default:
+				return v.(*Maze).MarshalBinary()
+			}
+		},
+		unmarshal: func(data []byte, v interface{}) error {
+			switch v := v.(type) {
+			case *Grid:
+				return v.UnmarshalBinary(data)
+			default:
+				return v.(*Maze).UnmarshalBinary(data)
+			}
+		},
+	},
+}
+
+func TestGridRoundTrip(t *testing.T) {
+	braid, err := NewBraid(3, NewBacktracking(3), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	division, err := NewRecursiveDivisionWithOptions(4, 3, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range codecs {
+		for _, size := range [][2]int{{1, 1}, {1, 7}, {5, 8}, {9, 4}} {
+			for _, init := range []Initer{NewWilson(1), braid, division} {
+				g := NewGrid(size[0], size[1])
+				if err := init.Init(g); err != nil {
+					t.Fatal(err)
+				}
+
+				data, err := c.marshal(g)
+				if err != nil {
+					t.Fatalf("%v: could not marshal grid: %v", c.name, err)
+				}
+				var got Grid
+				if err := c.unmarshal(data, &got); err != nil {
+					t.Fatalf("%v: could not unmarshal grid: %v", c.name, err)
+				}
+
+				if got.String() != g.String() {
+					t.Errorf("%v: grid changed after round trip:\n%v\n"+
+						"want:\n%v", c.name, got.String(), g.String())
+				}
+			}
+		}
+	}
+}
+
+func TestMazeRoundTrip(t *testing.T) {
+	newMaze := func() *Maze {
+		init, err := NewBraid(5, NewTruePrim(5), 0.3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := NewMazeWithDistance(6, 7, init, 3, 8, true, 11, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.SetSlip(0.2, SlipPerpendicular, 13); err != nil {
+			t.Fatal(err)
+		}
+		penalty, err := NewWallPenaltyReward(NewSparseReward(), 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shaping, err := NewPotentialShapingReward(penalty, 0.9)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SetRewarder(shaping); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SetEgocentricObs(3); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SetMaxSteps(50); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, action := range []int{0, 3, 1, 1, 2} {
+			if _, err := m.Step(action); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return m
+	}
+
+	for _, c := range codecs {
+		m := newMaze()
+		data, err := c.marshal(m)
+		if err != nil {
+			t.Fatalf("%v: could not marshal maze: %v", c.name, err)
+		}
+		var got Maze
+		if err := c.unmarshal(data, &got); err != nil {
+			t.Fatalf("%v: could not unmarshal maze: %v", c.name, err)
+		}
+
+		if got.String() != m.String() || got.Steps() != m.Steps() {
+			t.Errorf("%v: maze changed after round trip:\n%v\nwant:\n%v",
+				c.name, got.String(), m.String())
+		}
+		if !reflect.DeepEqual(got.ObservationSpec(), m.ObservationSpec()) {
+			t.Errorf("%v: observation spec changed after round trip",
+				c.name)
+		}
+		if !reflect.DeepEqual(got.MDP(), m.MDP()) {
+			t.Errorf("%v: MDP changed after round trip", c.name)
+		}
+
+		// Slipping, placement, and rewards must be the same in the
+		// reloaded maze after reseeding both mazes
+		seed := int64(7)
+		for episode := 0; episode < 5; episode++ {
+			var s *int64
+			if episode == 0 {
+				s = &seed
+			}
+			if !reflect.DeepEqual(got.Reset(s), m.Reset(s)) {
+				t.Fatalf("%v: observations differ after reset", c.name)
+			}
+
+			for step := 0; step < 20; step++ {
+				action := (step * 7) % Actions
+				want, err := m.Step(action)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err := got.Step(action)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(result, want) {
+					t.Fatalf("%v: step results differ: %+v, want %+v",
+						c.name, result, want)
+				}
+			}
+		}
+	}
+}
+
+// customReward is a Rewarder which cannot be serialized
+type customReward struct{}
+
+func (customReward) Reward(m *Maze, prev *Cell, action int, next *Cell,
+	hitWall bool) float64 {
+	return 0
+}
+
+func TestMarshalCustomRewarder(t *testing.T) {
+	m, err := NewMaze(3, 3, -1, -1, -1, -1, NewBacktracking(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetRewarder(customReward{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range codecs {
+		if _, err := c.marshal(m); err == nil {
+			t.Errorf("%v: expected error marshalling maze with custom "+
+				"rewarder", c.name)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	g := NewGrid(4, 5)
+	if err := NewBacktracking(2).Init(g); err != nil {
+		t.Fatal(err)
+	}
+	want := g.String()
+
+	valid, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Walls are inconsistent between the first two cells
+	inconsistent := append([]byte(nil), valid...)
+	inconsistent[len(gridMagic)+3] ^= eastWallBit
+
+	for _, data := range [][]byte{
+		valid[:len(valid)-1],
+		inconsistent,
+		[]byte("GMZG\x01\x80\x80\x80\x80\x10\x80\x80\x80\x80\x10"),
+	} {
+		if err := g.UnmarshalBinary(data); err == nil {
+			t.Errorf("expected error unmarshalling %q", data)
+		}
+		if g.String() != want {
+			t.Errorf("grid changed after failed unmarshal")
+		}
+	}
+
+	for _, data := range []string{
+		`{"rows":4294967296,"cols":4294967296,"walls":""}`,
+		`{"rows":-1,"cols":-1,"walls":""}`,
+		`{"rows":1,"cols":2,"walls":"Bw8="}`,
+		`{"rows":1,"cols":2,"walls":"DwA="}`,
+	} {
+		if err := json.Unmarshal([]byte(data), g); err == nil {
+			t.Errorf("expected error unmarshalling %v", data)
+		}
+		if g.String() != want {
+			t.Errorf("grid changed after failed unmarshal")
+		}
+	}
+}
+
+func TestUnmarshalNonFinite(t *testing.T) {
+	m, err := NewMaze(3, 3, -1, -1, -1, -1, NewBacktracking(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetSlip(0.5, SlipRandom, 1); err != nil {
+		t.Fatal(err)
+	}
+	penalty, err := NewWallPenaltyReward(NewStepCostReward(0.25), 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shaping, err := NewPotentialShapingReward(penalty, 0.875)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetRewarder(shaping); err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := m.String()
+
+	// encode returns the binary representation of x
+	encode := func(x float64) []byte {
+		var buf bytes.Buffer
+		writeFloat(&buf, x)
+		return buf.Bytes()
+	}
+
+	// The slip probability, discount, penalty, and cost are each
+	// replaced by non-finite values
+	for _, value := range []float64{0.5, 0.875, 0.75, 0.25} {
+		for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			if bytes.Count(valid, encode(value)) != 1 {
+				t.Fatalf("could not find value %v in binary maze", value)
+			}
+			data := bytes.Replace(valid, encode(value), encode(bad), 1)
+
+			if err := m.UnmarshalBinary(data); err == nil {
+				t.Errorf("expected error unmarshalling maze with %v "+
+					"replaced by %v", value, bad)
+			}
+			if m.String() != want || m.slip.prob != 0.5 {
+				t.Errorf("maze changed after failed unmarshal")
+			}
+		}
+	}
+}
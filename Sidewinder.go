@@ -0,0 +1,105 @@
+package gomaze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// defaultCloseProb is the default probability with which Sidewinder
+// closes a run of cells
+const defaultCloseProb float64 = 0.5
+
+// Sidewinder initializes a grid into a maze using the sidewinder
+// algorithm.
+//
+// The bias determines the direction in which runs of cells are carved
+// and closed. For example, with a bias of NE, runs of cells are carved
+// to the east, and a run is closed by linking a random cell in the run
+// to its northern neighbour. The side of the maze given by the bias
+// (in this case the northern side) is spanned by a single corridor.
+type Sidewinder struct {
+	rng  *rand.Rand
+	bias BiasDirection
+
+	// closeProb is the probability of closing a run of cells at each
+	// cell not on the boundary of the maze
+	closeProb float64
+}
+
+// NewSidewinder returns a new Sidewinder with a random bias which
+// closes runs with probability 0.5
+func NewSidewinder(seed int64) Initer {
+	init := &Sidewinder{
+		rng:       rand.New(rand.NewSource(seed)),
+		closeProb: defaultCloseProb,
+	}
+
+	// Set a random bias
+	biases := []BiasDirection{NW, NE, SW, SE}
+
+	init.bias = biases[init.rng.Intn(len(biases))]
+	return init
+}
+
+// NewSidewinderWithBias returns a new Sidewinder with bias given by
+// bias, which closes runs with probability closeProb
+func NewSidewinderWithBias(seed int64, bias BiasDirection,
+	closeProb float64) (Initer, error) {
+	if bias != NW && bias != NE && bias != SW && bias != SE {
+		return nil, fmt.Errorf("newSidewinderWithBias: could not create "+
+			"sidewinder with unknown bias %v", bias)
+	}
+	if closeProb < 0 || closeProb > 1 {
+		return nil, fmt.Errorf("newSidewinderWithBias: close probability "+
+			"%v ∉ [0, 1]", closeProb)
+	}
+
+	return &Sidewinder{
+		rng:       rand.New(rand.NewSource(seed)),
+		bias:      bias,
+		closeProb: closeProb,
+	}, nil
+}
+
+// Init initializes a grid using the sidewinder algorithm
+func (s *Sidewinder) Init(g *Grid) error {
+	// closeDir is the direction in which runs are closed, and carveDir
+	// is the direction in which runs are carved
+	closeDir, carveDir, err := bias(s.bias)
+	if err != nil {
+		return fmt.Errorf("init: could not get bias directions: %v", err)
+	}
+
+	run := make([]*Cell, 0, g.Cols())
+	for r := 0; r < g.Rows(); r++ {
+		// Start each run on the side of the row opposite to the
+		// direction of carving
+		var cell *Cell
+		if carveDir(g.cells[g.Index(0, r)]) != nil {
+			cell = g.cells[g.Index(0, r)]
+		} else {
+			cell = g.cells[g.Index(g.Cols()-1, r)]
+		}
+
+		run = run[:0]
+		for ; cell != nil; cell = carveDir(cell) {
+			run = append(run, cell)
+
+			atCarveBoundary := carveDir(cell) == nil
+			atCloseBoundary := closeDir(cell) == nil
+			shouldClose := atCarveBoundary ||
+				(!atCloseBoundary && s.rng.Float64() < s.closeProb)
+
+			if shouldClose {
+				member := run[s.rng.Intn(len(run))]
+				if closeDir(member) != nil {
+					member.Link(closeDir(member))
+				}
+				run = run[:0]
+			} else {
+				cell.Link(carveDir(cell))
+			}
+		}
+	}
+	return nil
+}
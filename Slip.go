@@ -0,0 +1,102 @@
+package gomaze
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SlipMode determines how the action taken by a player is replaced
+// when the player slips
+type SlipMode int
+
+const (
+	// SlipRandom replaces the action with an action chosen uniformly
+	// at random from all actions, including the intended action
+	SlipRandom SlipMode = iota
+
+	// SlipPerpendicular replaces the action with one of the two
+	// actions perpendicular to it, chosen uniformly at random
+	SlipPerpendicular
+)
+
+// slip implements stochastic transitions, where the action taken by
+// a player is replaced with some probability
+type slip struct {
+	rng  *rand.Rand
+	seed int64 // Seed rng was last seeded with
+	prob float64
+	mode SlipMode
+}
+
+// perpendicular returns the two actions perpendicular to action
+func perpendicular(action int) [2]int {
+	if action == 0 || action == 1 {
+		return [2]int{2, 3}
+	}
+	return [2]int{0, 1}
+}
+
+// action returns the action actually taken when action is chosen
+func (s *slip) action(action int) int {
+	if s.rng.Float64() >= s.prob {
+		return action
+	}
+
+	switch s.mode {
+	case SlipPerpendicular:
+		return perpendicular(action)[s.rng.Intn(2)]
+
+	default:
+		return s.rng.Intn(Actions)
+	}
+}
+
+// actions returns the actions which may actually be taken when action
+// is chosen, along with their probabilities
+func (s *slip) actions(action int) ([]int, []float64) {
+	switch s.mode {
+	case SlipPerpendicular:
+		perp := perpendicular(action)
+		return []int{action, perp[0], perp[1]},
+			[]float64{1 - s.prob, s.prob / 2, s.prob / 2}
+
+	default:
+		actions := make([]int, Actions)
+		probs := make([]float64, Actions)
+		for a := range actions {
+			actions[a] = a
+			probs[a] = s.prob / Actions
+		}
+		probs[action] += 1 - s.prob
+		return actions, probs
+	}
+}
+
+// SetSlip makes transitions in the maze stochastic. When the player
+// takes an action with Step, the action is replaced with probability
+// prob according to mode. The seed seeds the random number generator
+// used to determine when and how the player slips. The transitions
+// used by MDP and OptimalValues account for slipping. A probability of
+// 0 makes transitions deterministic.
+func (m *Maze) SetSlip(prob float64, mode SlipMode, seed int64) error {
+	if prob < 0 || prob > 1 || math.IsNaN(prob) {
+		return fmt.Errorf("setSlip: probability %v ∉ [0, 1]", prob)
+	}
+	if mode != SlipRandom && mode != SlipPerpendicular {
+		return fmt.Errorf("setSlip: unknown slip mode %v", mode)
+	}
+
+	if prob == 0 {
+		m.slip = nil
+		return nil
+	}
+
+	m.slip = &slip{
+		rng:  rand.New(rand.NewSource(seed)),
+		seed: seed,
+		prob: prob,
+		mode: mode,
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package gomaze
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSetSlipInvalid(t *testing.T) {
+	m, err := NewMaze(3, 3, -1, -1, -1, -1, NewBacktracking(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prob := range []float64{-0.1, 1.1, math.NaN(), math.Inf(1)} {
+		if err := m.SetSlip(prob, SlipRandom, 1); err == nil {
+			t.Errorf("expected error for slip probability %v", prob)
+		}
+	}
+	if err := m.SetSlip(0.5, SlipMode(2), 1); err == nil {
+		t.Errorf("expected error for unknown slip mode")
+	}
+	if m.slip != nil {
+		t.Errorf("slipping enabled after invalid calls to SetSlip")
+	}
+}
+
+func TestSlipProbabilities(t *testing.T) {
+	// Probability of taking the intended action with a slip probability
+	// of 0.3 in each mode
+	want := map[SlipMode]float64{
+		SlipRandom:        0.7 + 0.3/Actions,
+		SlipPerpendicular: 0.7,
+	}
+
+	for mode, wantIntended := range want {
+		s := &slip{prob: 0.3, mode: mode}
+		for action := 0; action < Actions; action++ {
+			actions, probs := s.actions(action)
+
+			total, intended := 0.0, 0.0
+			for i, p := range probs {
+				total += p
+				if actions[i] == action {
+					intended += p
+				}
+			}
+			if math.Abs(total-1) > 1e-12 {
+				t.Errorf("mode %v, action %v: probabilities sum to %v",
+					mode, action, total)
+			}
+			if math.Abs(intended-wantIntended) > 1e-12 {
+				t.Errorf("mode %v, action %v: intended action has "+
+					"probability %v", mode, action, intended)
+			}
+		}
+	}
+}
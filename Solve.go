@@ -0,0 +1,91 @@
+package gomaze
+
+import (
+	"fmt"
+)
+
+// ShortestPath returns the shortest path from cell from to cell to in
+// the grid, moving only between linked cells. The path is returned as
+// the sequence of cells visited, including from and to, as well as the
+// sequence of actions taken to move between these cells. Actions are
+// numbered as in Maze.Step.
+func (g *Grid) ShortestPath(from, to *Cell) ([]*Cell, []int, error) {
+	if from == nil || to == nil {
+		return nil, nil, fmt.Errorf("shortestPath: cells must be non-nil")
+	}
+
+	// Breadth-first search from from until reaching to
+	parents := map[*Cell]*Cell{from: nil}
+	queue := []*Cell{from}
+	for len(queue) > 0 && queue[0] != to {
+		cell := queue[0]
+		queue = queue[1:]
+
+		for _, neighbour := range cell.Links() {
+			if _, seen := parents[neighbour]; !seen {
+				parents[neighbour] = cell
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	if _, found := parents[to]; !found {
+		return nil, nil, fmt.Errorf("shortestPath: no path from (%v, %v) "+
+			"to (%v, %v)", from.Col(), from.Row(), to.Col(), to.Row())
+	}
+
+	// Walk back from to, recording the path in reverse
+	path := make([]*Cell, 0)
+	for cell := to; cell != nil; cell = parents[cell] {
+		path = append(path, cell)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	actions := make([]int, len(path)-1)
+	for i := range actions {
+		action, err := actionBetween(path[i], path[i+1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("shortestPath: %v", err)
+		}
+		actions[i] = action
+	}
+
+	return path, actions, nil
+}
+
+// ShortestPath returns the shortest path from the starting cell to the
+// goal cell of the maze. The path is returned as the sequence of cells
+// visited, including the start and goal, as well as the sequence of
+// actions to take in Step to follow the path.
+func (m *Maze) ShortestPath() ([]*Cell, []int, error) {
+	path, actions, err := m.Grid.ShortestPath(m.start, m.goal)
+	if err != nil {
+		return nil, nil, fmt.Errorf("shortestPath: %v", err)
+	}
+
+	return path, actions, nil
+}
+
+// actionBetween returns the action which moves a player from cell
+// from to its neighbouring cell to
+func actionBetween(from, to *Cell) (int, error) {
+	switch to {
+	case from.North():
+		return 0, nil
+
+	case from.South():
+		return 1, nil
+
+	case from.West():
+		return 2, nil
+
+	case from.East():
+		return 3, nil
+
+	default:
+		return -1, fmt.Errorf("actionBetween: cells (%v, %v) and (%v, %v) "+
+			"are not neighbours", from.Col(), from.Row(), to.Col(), to.Row())
+	}
+}
@@ -0,0 +1,276 @@
+package gomaze
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextStyle determines the characters used to draw mazes as text
+type TextStyle int
+
+const (
+	// ASCIIStyle draws mazes with +, -, and | characters
+	ASCIIStyle TextStyle = iota
+
+	// UnicodeStyle draws mazes with Unicode box-drawing characters
+	UnicodeStyle
+
+	// HalfBlockStyle draws mazes compactly with Unicode block
+	// characters, using a single character for each cell or wall
+	// horizontally and half a character vertically. Since a cell
+	// shares its character with the wall to its north, markers hide
+	// the wall to the north of marked cells.
+	HalfBlockStyle
+)
+
+// ANSI escape codes for colouring markers
+const (
+	ansiReset  = "\x1b[0m"
+	ansiPlayer = "\x1b[1;31m"
+	ansiGoal   = "\x1b[1;32m"
+	ansiStart  = "\x1b[1;34m"
+	ansiPath   = "\x1b[33m"
+)
+
+// boxCorners holds the box-drawing character for each combination of
+// walls meeting at a corner, indexed by a bitmask of up (1), down (2),
+// left (4), and right (8) walls
+var boxCorners = [16]string{
+	" ", "╵", "╷", "│", "╴", "┘", "┐", "┤",
+	"╶", "└", "┌", "├", "─", "┴", "┬", "┼",
+}
+
+// TextRenderer renders grids and mazes as text
+type TextRenderer struct {
+	style TextStyle
+
+	// Colour determines whether markers are coloured with ANSI escape
+	// codes
+	Colour bool
+
+	// Player, Goal, Start, and Path are the markers drawn in the cell
+	// of the player, the goal cell, the starting cell, and the cells
+	// along a path respectively. Each marker should be a single
+	// character wide. Empty markers are not drawn.
+	Player, Goal, Start, Path string
+}
+
+// NewTextRenderer returns a new TextRenderer which draws mazes in the
+// given style. By default, the player is marked with x, the goal with
+// G, and paths with ., while the start is not marked.
+func NewTextRenderer(style TextStyle) (*TextRenderer, error) {
+	if style != ASCIIStyle && style != UnicodeStyle &&
+		style != HalfBlockStyle {
+		return nil, fmt.Errorf("newTextRenderer: unknown style %v", style)
+	}
+
+	return &TextRenderer{
+		style:  style,
+		Player: "x",
+		Goal:   "G",
+		Path:   ".",
+	}, nil
+}
+
+// defaultTextRenderer returns the TextRenderer used by Grid.String and
+// Maze.String
+func defaultTextRenderer() *TextRenderer {
+	t, _ := NewTextRenderer(ASCIIStyle)
+	return t
+}
+
+// RenderGrid returns the text representation of g, with the cells of
+// path marked. If path is nil, no path is drawn.
+func (t *TextRenderer) RenderGrid(g *Grid, path []*Cell) string {
+	return t.render(g, t.markers(nil, nil, nil, path))
+}
+
+// RenderMaze returns the text representation of m, with the player,
+// start, goal, and the cells of path marked. If path is nil, no path
+// is drawn.
+func (t *TextRenderer) RenderMaze(m *Maze, path []*Cell) string {
+	return t.render(m.Grid, t.markers(m.player.in, m.goal, m.start, path))
+}
+
+// markers returns a function which returns the marker to draw in a
+// cell, or the empty string if no marker should be drawn
+func (t *TextRenderer) markers(player, goal, start *Cell,
+	path []*Cell) func(*Cell) string {
+	onPath := make(map[*Cell]struct{}, len(path))
+	for _, cell := range path {
+		onPath[cell] = struct{}{}
+	}
+
+	colour := func(marker, code string) string {
+		if !t.Colour || marker == "" {
+			return marker
+		}
+		return code + marker + ansiReset
+	}
+
+	return func(cell *Cell) string {
+		switch {
+		case cell == goal && t.Goal != "":
+			return colour(t.Goal, ansiGoal)
+
+		case cell == player && t.Player != "":
+			return colour(t.Player, ansiPlayer)
+
+		case cell == start && t.Start != "":
+			return colour(t.Start, ansiStart)
+		}
+
+		if _, ok := onPath[cell]; ok {
+			return colour(t.Path, ansiPath)
+		}
+		return ""
+	}
+}
+
+// render returns the text representation of g with cells marked by
+// marker
+func (t *TextRenderer) render(g *Grid, marker func(*Cell) string) string {
+	// hWall returns whether there is a wall to the north of the cell
+	// at column c and row r, where r may be g.Rows() for the southern
+	// boundary of the grid
+	hWall := func(c, r int) bool {
+		if r == 0 || r == g.Rows() {
+			return true
+		}
+		return !g.cells[g.Index(c, r-1)].CanMoveSouth()
+	}
+
+	// vWall returns whether there is a wall to the west of the cell at
+	// column c and row r, where c may be g.Cols() for the eastern
+	// boundary of the grid
+	vWall := func(c, r int) bool {
+		if c == 0 || c == g.Cols() {
+			return true
+		}
+		return !g.cells[g.Index(c-1, r)].CanMoveEast()
+	}
+
+	// body returns the body of a cell three characters wide
+	body := func(c, r int) string {
+		if m := marker(g.cells[g.Index(c, r)]); m != "" {
+			return " " + m + " "
+		}
+		return "   "
+	}
+
+	var out strings.Builder
+	switch t.style {
+	case UnicodeStyle:
+		for r := 0; r <= g.Rows(); r++ {
+			for c := 0; c <= g.Cols(); c++ {
+				corner := 0
+				if r > 0 && vWall(c, r-1) {
+					corner |= 1
+				}
+				if r < g.Rows() && vWall(c, r) {
+					corner |= 2
+				}
+				if c > 0 && hWall(c-1, r) {
+					corner |= 4
+				}
+				if c < g.Cols() && hWall(c, r) {
+					corner |= 8
+				}
+				out.WriteString(boxCorners[corner])
+
+				if c < g.Cols() {
+					out.WriteString(choose(hWall(c, r), "───", "   "))
+				}
+			}
+			out.WriteString("\n")
+
+			if r < g.Rows() {
+				for c := 0; c < g.Cols(); c++ {
+					out.WriteString(choose(vWall(c, r), "│", " "))
+					out.WriteString(body(c, r))
+				}
+				out.WriteString("│\n")
+			}
+		}
+
+	case HalfBlockStyle:
+		// The grid is drawn as blocks, where each cell, wall, and
+		// corner is a single block, and each character holds two
+		// vertically adjacent blocks
+		filled := func(x, y int) bool {
+			switch {
+			case y > 2*g.Rows():
+				return false
+
+			case x%2 == 0 && y%2 == 0:
+				return true
+
+			case y%2 == 0:
+				return hWall((x-1)/2, y/2)
+
+			case x%2 == 0:
+				return vWall(x/2, (y-1)/2)
+
+			default:
+				return false
+			}
+		}
+
+		for y := 0; y <= 2*g.Rows(); y += 2 {
+			for x := 0; x <= 2*g.Cols(); x++ {
+				// Only the lower block of a character can be a cell
+				if x%2 == 1 && y+1 < 2*g.Rows() {
+					cell := g.cells[g.Index((x-1)/2, y/2)]
+					if m := marker(cell); m != "" {
+						out.WriteString(m)
+						continue
+					}
+				}
+
+				top, bottom := filled(x, y), filled(x, y+1)
+				switch {
+				case top && bottom:
+					out.WriteString("█")
+
+				case top:
+					out.WriteString("▀")
+
+				case bottom:
+					out.WriteString("▄")
+
+				default:
+					out.WriteString(" ")
+				}
+			}
+			out.WriteString("\n")
+		}
+
+	default:
+		for r := 0; r <= g.Rows(); r++ {
+			out.WriteString("+")
+			for c := 0; c < g.Cols(); c++ {
+				out.WriteString(choose(hWall(c, r), "---", "   "))
+				out.WriteString("+")
+			}
+			out.WriteString("\n")
+
+			if r < g.Rows() {
+				for c := 0; c < g.Cols(); c++ {
+					out.WriteString(choose(vWall(c, r), "|", " "))
+					out.WriteString(body(c, r))
+				}
+				out.WriteString("|\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// choose returns a if cond is true and b otherwise
+func choose(cond bool, a, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,75 @@
+package gomaze
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// valueTolerance is the largest change in any state value for
+	// which value iteration is considered to have converged
+	valueTolerance float64 = 1e-12
+
+	// maxValueIterations is the maximum number of sweeps over the
+	// state space performed by value iteration before giving up
+	maxValueIterations int = 1_000_000
+)
+
+// OptimalValues returns the optimal state-value function V* and
+// action-value function Q* of the maze for the given discount factor,
+// under the rewards given by the maze's Rewarder. Both are indexed by
+// state as in Grid.Index and Maze.OneHot, and Q* is further indexed by
+// action as in Step, so that Q*(s, a) is given by q[s][a]. The goal is
+// an absorbing state, and so has a value of 0.
+//
+// The values are computed by value iteration and account for slipping
+// if set with SetSlip. If transitions are deterministic, value
+// iteration converges to the exact values after at most as many sweeps
+// as the length of the longest shortest path to the goal. Otherwise,
+// value iteration is run until no value changes by more than 1e-12.
+func (m *Maze) OptimalValues(discount float64) ([]float64, [][]float64,
+	error) {
+	if discount < 0 || discount > 1 {
+		return nil, nil, fmt.Errorf("optimalValues: discount %v ∉ [0, 1]",
+			discount)
+	}
+
+	v := make([]float64, m.Len())
+	q := make([][]float64, m.Len())
+	for s := range q {
+		q[s] = make([]float64, Actions)
+	}
+
+	for iter := 0; iter < maxValueIterations; iter++ {
+		delta := 0.0
+		for s, cell := range m.cells {
+			if cell == m.goal {
+				continue
+			}
+
+			best := math.Inf(-1)
+			for a := 0; a < Actions; a++ {
+				q[s][a] = 0.0
+				for _, t := range m.transitions(cell, a) {
+					value := m.reward(cell, t.action, t.next)
+					if t.next != m.goal {
+						next := m.Index(t.next.Col(), t.next.Row())
+						value += discount * v[next]
+					}
+					q[s][a] += t.prob * value
+				}
+				best = math.Max(best, q[s][a])
+			}
+
+			delta = math.Max(delta, math.Abs(best-v[s]))
+			v[s] = best
+		}
+
+		if delta <= valueTolerance {
+			return v, q, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("optimalValues: value iteration did not "+
+		"converge in %v iterations", maxValueIterations)
+}